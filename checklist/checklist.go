@@ -0,0 +1,173 @@
+// Package checklist decides which recurring items, like checking email each morning or filling in the time
+// sheet on Fridays, go in a day note, and under which sections.
+package checklist
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MorningChecklist is the section items go under when they don't name one.
+const MorningChecklist = "Morning Checklist"
+
+type Item struct {
+	// ID names the item for gnote, like timesheet for the item Friday's timesheet export is linked from
+	ID       string
+	Label    string
+	Schedule Schedule
+	// Section is the heading the item is listed under, without the #s
+	Section string
+	// Link is a note linked after the label
+	Link string
+}
+
+// Section is a heading in the day note and the items due under it.
+type Section struct {
+	Name  string
+	Items []Item
+}
+
+// Default is the checklist when none is configured.
+var Default = []Item{
+	{ID: "email", Label: "check email"},
+	{ID: "calendar", Label: "check calendar"},
+	{ID: "slack", Label: "check Slack"},
+	{ID: "home-todo", Label: "check home todo"},
+	{ID: "timesheet", Label: "time sheet", Schedule: mustParseSchedule("friday")},
+	{ID: "working-wednesday", Label: "working Wednesday", Schedule: mustParseSchedule("wednesday")},
+	{ID: "expenses", Label: "WFH expenses in Concur", Schedule: mustParseSchedule("last week")},
+}
+
+// Due are the items due on date grouped into their sections, with the sections and the items in each
+// in the order they're listed.
+func Due(items []Item, date time.Time) []Section {
+	var sections []Section
+	index := map[string]int{}
+	for _, item := range items {
+		if !item.Schedule.Due(date) {
+			continue
+		}
+		name := item.Section
+		if name == "" {
+			name = MorningChecklist
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(sections)
+			index[name] = i
+			sections = append(sections, Section{Name: name})
+		}
+		sections[i].Items = append(sections[i].Items, item)
+	}
+	return sections
+}
+
+// Find is the item with the id in the sections, or nil when it isn't due.
+func Find(sections []Section, id string) *Item {
+	for i := range sections {
+		for j := range sections[i].Items {
+			if sections[i].Items[j].ID == id {
+				return &sections[i].Items[j]
+			}
+		}
+	}
+	return nil
+}
+
+// Schedule is the days an item is due on. The zero Schedule is every day.
+type Schedule struct {
+	rules []func(time.Time) bool
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseSchedule reads a schedule like "friday", "mon, wed" or "last week". An item is due on a day
+// any of the comma separated parts match:
+//
+//	daily          every day, as is an empty schedule
+//	weekdays       Monday to Friday
+//	friday, fri    that day each week
+//	last friday    the last one in the month
+//	last week      Monday to Friday in the last seven days of the month
+//	day 15         that day of the month
+//	last day       the last day of the month
+func ParseSchedule(schedule string) (Schedule, error) {
+	var s Schedule
+	for _, part := range strings.Split(schedule, ",") {
+		part = strings.Join(strings.Fields(strings.ToLower(part)), " ")
+		if part == "" {
+			continue
+		}
+		rule, err := parseRule(part)
+		if err != nil {
+			return Schedule{}, fmt.Errorf("reading schedule %q: %w", schedule, err)
+		}
+		s.rules = append(s.rules, rule)
+	}
+	return s, nil
+}
+
+func mustParseSchedule(schedule string) Schedule {
+	s, err := ParseSchedule(schedule)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func parseRule(part string) (func(time.Time) bool, error) {
+	switch part {
+	case "daily":
+		return func(time.Time) bool { return true }, nil
+	case "weekdays":
+		return isWeekday, nil
+	case "last week":
+		return func(date time.Time) bool {
+			return isWeekday(date) && date.AddDate(0, 0, 7).Month() != date.Month()
+		}, nil
+	case "last day":
+		return func(date time.Time) bool { return date.AddDate(0, 0, 1).Month() != date.Month() }, nil
+	}
+	if weekday, ok := weekdays[part]; ok {
+		return func(date time.Time) bool { return date.Weekday() == weekday }, nil
+	}
+	if name, ok := strings.CutPrefix(part, "last "); ok {
+		if weekday, ok := weekdays[name]; ok {
+			return func(date time.Time) bool {
+				return date.Weekday() == weekday && date.AddDate(0, 0, 7).Month() != date.Month()
+			}, nil
+		}
+	}
+	if n, ok := strings.CutPrefix(part, "day "); ok {
+		day, err := strconv.Atoi(n)
+		if err != nil || day < 1 || day > 31 {
+			return nil, fmt.Errorf("%q should be a day of the month from 1 to 31", n)
+		}
+		return func(date time.Time) bool { return date.Day() == day }, nil
+	}
+	return nil, fmt.Errorf("unknown schedule %q, use daily, weekdays, a weekday like friday, last friday, last week, day 15 or last day", part)
+}
+
+func isWeekday(date time.Time) bool {
+	return date.Weekday() != time.Saturday && date.Weekday() != time.Sunday
+}
+
+// Due reports whether the schedule has the item due on date.
+func (s Schedule) Due(date time.Time) bool {
+	if len(s.rules) == 0 {
+		return true
+	}
+	for _, rule := range s.rules {
+		if rule(date) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package checklist
+
+import (
+	"testing"
+	"time"
+)
+
+func date(month time.Month, day int) time.Time {
+	return time.Date(2024, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestScheduleDue(t *testing.T) {
+	tests := []struct {
+		schedule string
+		due      []time.Time
+		notDue   []time.Time
+	}{
+		{schedule: "", due: []time.Time{date(time.March, 2), date(time.March, 4)}},
+		{schedule: "daily", due: []time.Time{date(time.March, 3)}},
+		{schedule: "weekdays", due: []time.Time{date(time.March, 4), date(time.March, 8)}, notDue: []time.Time{date(time.March, 9), date(time.March, 10)}},
+		{schedule: "Friday", due: []time.Time{date(time.March, 8)}, notDue: []time.Time{date(time.March, 7)}},
+		{schedule: "mon, wed", due: []time.Time{date(time.March, 4), date(time.March, 6)}, notDue: []time.Time{date(time.March, 5)}},
+		{schedule: "last friday", due: []time.Time{date(time.March, 29)}, notDue: []time.Time{date(time.March, 22)}},
+		// The last seven days of March 2024 are the 25th to the 31st
+		{schedule: "last week", due: []time.Time{date(time.March, 25), date(time.March, 29)}, notDue: []time.Time{date(time.March, 22), date(time.March, 30)}},
+		{schedule: "day 15", due: []time.Time{date(time.March, 15)}, notDue: []time.Time{date(time.March, 16)}},
+		{schedule: "last day", due: []time.Time{date(time.February, 29), date(time.March, 31)}, notDue: []time.Time{date(time.February, 28)}},
+	}
+	for _, tt := range tests {
+		s, err := ParseSchedule(tt.schedule)
+		if err != nil {
+			t.Errorf("ParseSchedule(%q) returned an error: %v", tt.schedule, err)
+			continue
+		}
+		for _, d := range tt.due {
+			if !s.Due(d) {
+				t.Errorf("Expected %q to be due on %s", tt.schedule, d.Format("Mon 2 Jan"))
+			}
+		}
+		for _, d := range tt.notDue {
+			if s.Due(d) {
+				t.Errorf("Expected %q not to be due on %s", tt.schedule, d.Format("Mon 2 Jan"))
+			}
+		}
+	}
+
+	for _, schedule := range []string{"fortnightly", "day 32", "last month", "fri, sometimes"} {
+		if _, err := ParseSchedule(schedule); err == nil {
+			t.Errorf("Expected an error for %q", schedule)
+		}
+	}
+}
+
+func TestDue(t *testing.T) {
+	items := []Item{
+		{ID: "a", Label: "A"},
+		{ID: "b", Label: "B", Section: "Admin"},
+		{ID: "c", Label: "C", Schedule: mustParseSchedule("sunday")},
+		{ID: "d", Label: "D"},
+	}
+	sections := Due(items, date(time.March, 4))
+	if len(sections) != 2 || sections[0].Name != MorningChecklist || sections[1].Name != "Admin" {
+		t.Fatalf("Expected the Morning Checklist then Admin, but got %+v", sections)
+	}
+	if got := sections[0].Items; len(got) != 2 || got[0].ID != "a" || got[1].ID != "d" {
+		t.Errorf("Expected a and d in the Morning Checklist, but got %+v", got)
+	}
+
+	Find(sections, "b").Link = "Invoices"
+	if items[1].Link != "" {
+		t.Error("Expected linking a due item to leave the items alone")
+	}
+	if Find(sections, "c") != nil {
+		t.Error("Expected c not to be due on a Monday")
+	}
+}
@@ -0,0 +1,89 @@
+// Package clock tells commands what time it is, and which day that counts as. Commands take a Clock
+// instead of calling time.Now so tests can pin the time.
+package clock
+
+import (
+	"fmt"
+	"time"
+)
+
+type Clock interface {
+	Now() time.Time
+}
+
+// Func adapts a function to a Clock.
+type Func func() time.Time
+
+func (f Func) Now() time.Time {
+	return f()
+}
+
+// System is the real clock.
+var System Clock = Func(time.Now)
+
+// Fixed is a clock stopped at t.
+func Fixed(t time.Time) Clock {
+	return Func(func() time.Time { return t })
+}
+
+// Day decides which day a moment belongs to. Days are reckoned in Location and start StartsAt
+// after midnight, so with a 4am start, working until 1am still counts as the day before.
+type Day struct {
+	Location *time.Location
+	StartsAt time.Duration
+}
+
+// NewDay reads the timezone, an IANA name like "Europe/London" or empty for the local one,
+// and when the day starts, like "04:00" or empty for midnight.
+func NewDay(timezone string, startsAt string) (Day, error) {
+	day := Day{Location: time.Local}
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return Day{}, fmt.Errorf("timezone: %w", err)
+		}
+		day.Location = loc
+	}
+	if startsAt != "" {
+		t, err := time.Parse("15:04", startsAt)
+		if err != nil {
+			return Day{}, fmt.Errorf("day_starts_at should look like 04:00, not %q", startsAt)
+		}
+		day.StartsAt = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	return day, nil
+}
+
+// Today is the day it is on the clock, at midnight in the day's location.
+func (d Day) Today(c Clock) time.Time {
+	return d.Of(c.Now())
+}
+
+// Of is the day t belongs to, at midnight in the day's location.
+func (d Day) Of(t time.Time) time.Time {
+	local := t.In(d.location())
+	date := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	if local.Before(d.Start(date)) {
+		date = date.AddDate(0, 0, -1)
+	}
+	return date
+}
+
+// Start is the moment the day of date begins.
+func (d Day) Start(date time.Time) time.Time {
+	local := date.In(d.location())
+	minutes := int(d.StartsAt / time.Minute)
+	return time.Date(local.Year(), local.Month(), local.Day(), minutes/60, minutes%60, 0, 0, local.Location())
+}
+
+// End is the moment the day of date is over, when the next one starts.
+func (d Day) End(date time.Time) time.Time {
+	return d.Start(date.In(d.location()).AddDate(0, 0, 1))
+}
+
+func (d Day) location() *time.Location {
+	if d.Location == nil {
+		return time.Local
+	}
+	return d.Location
+}
@@ -0,0 +1,66 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDayOf(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no timezone data:", err)
+	}
+	lateStart, err := NewDay("America/New_York", "04:00")
+	if err != nil {
+		t.Fatalf("NewDay returned an error: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		day      Day
+		now      time.Time
+		expected string
+	}{
+		{"midnight start", Day{Location: time.UTC}, time.Date(2024, time.March, 6, 0, 30, 0, 0, time.UTC), "2024-03-06"},
+		{"before the day starts", lateStart, time.Date(2024, time.March, 6, 1, 30, 0, 0, newYork), "2024-03-05"},
+		{"when the day starts", lateStart, time.Date(2024, time.March, 6, 4, 0, 0, 0, newYork), "2024-03-06"},
+		// 02:00 UTC on the 7th is still the evening of the 6th in New York
+		{"other timezone", lateStart, time.Date(2024, time.March, 7, 2, 0, 0, 0, time.UTC), "2024-03-06"},
+		{"new year's eve", lateStart, time.Date(2025, time.January, 1, 3, 59, 0, 0, newYork), "2024-12-31"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.day.Today(Fixed(tc.now))
+			if got.Format("2006-01-02") != tc.expected {
+				t.Errorf("Expected %s, but got %s", tc.expected, got.Format("2006-01-02"))
+			}
+			if got.Location() != tc.day.Location {
+				t.Errorf("Expected the day in %s, but got %s", tc.day.Location, got.Location())
+			}
+		})
+	}
+}
+
+func TestDayStartAndEnd(t *testing.T) {
+	day, err := NewDay("UTC", "04:30")
+	if err != nil {
+		t.Fatalf("NewDay returned an error: %v", err)
+	}
+	date := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+	if got := day.Start(date); !got.Equal(time.Date(2024, time.March, 5, 4, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected the day to start at 04:30, but got %s", got)
+	}
+	if got := day.End(date); !got.Equal(time.Date(2024, time.March, 6, 4, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected the day to end at 04:30 the next day, but got %s", got)
+	}
+}
+
+func TestNewDayRejectsBadSettings(t *testing.T) {
+	if _, err := NewDay("Nowhere/Special", ""); err == nil {
+		t.Error("Expected an unknown timezone to be rejected")
+	}
+	if _, err := NewDay("", "4am"); err == nil {
+		t.Error("Expected a start time that isn't HH:MM to be rejected")
+	}
+}
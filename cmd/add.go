@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gnote/clock"
+	"gnote/config"
+	"gnote/filelock"
+
+	"github.com/spf13/cobra"
+)
+
+var addTo string
+
+var addCmd = &cobra.Command{
+	Use:   "add [text]",
+	Short: "Capture a thought in today's note without opening an editor",
+	Long: `Appends a timestamped bullet to the capture section of today's day note, creating the note if needed.
+The text comes from the arguments, or from stdin when there are none, e.g. 'pbpaste | gnote add'.
+--to PROJ-1 captures into the project's description note instead.
+
+The section is capture_section in the config, "## Inbox" when not set.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			return
+		}
+
+		text := strings.Join(args, " ")
+		if len(args) == 0 {
+			if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+				fmt.Println("Nothing to add; give the text as arguments or on stdin")
+				return
+			}
+			input, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				fmt.Println("Error reading stdin:", err)
+				return
+			}
+			text = string(input)
+		}
+		if strings.TrimSpace(text) == "" {
+			fmt.Println("Nothing to add")
+			return
+		}
+
+		notePath, err := capture(cfg, clock.System, addTo, text)
+		if err != nil {
+			fmt.Println("Error capturing:", err)
+			return
+		}
+		fmt.Printf("Added to %s\n", relativeTo(cfg.VaultPath, notePath))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(addCmd)
+	addCmd.Flags().StringVar(&addTo, "to", "", "Capture into this project's description note")
+}
+
+// capture appends text to the capture section of today's day note, or of the project's description note
+// when project is given, and returns the note's path.
+func capture(cfg *config.Config, c clock.Clock, project string, text string) (string, error) {
+	day, err := dayBoundary(cfg)
+	if err != nil {
+		return "", err
+	}
+	date := day.Today(c)
+	at := c.Now().In(date.Location())
+
+	if project != "" {
+		projectPath := filepath.Join(cfg.VaultPath, cfg.ProjectsPath, project)
+		notePath := projectNotePath(projectPath)
+		if _, err := os.Stat(notePath); err != nil {
+			return "", err
+		}
+		// Project notes aren't dated, so the bullet carries the day too
+		bullet := captureBullet(at.Format("2006-01-02 15:04"), text)
+		return notePath, editNote(notePath, nil, func(content string) string {
+			return appendToSection(content, cfg.CaptureHeading(), bullet)
+		})
+	}
+
+	return appendToDayNote(cfg, date, cfg.CaptureHeading(), captureBullet(at.Format("15:04"), text))
+}
+
+// appendToDayNote adds text to the section under heading in the day note for date, creating the note
+// first if needed, and returns the note's path.
+func appendToDayNote(cfg *config.Config, date time.Time, heading string, text string) (string, error) {
+	return editDayNote(cfg, date, func(content string) string {
+		return appendToSection(content, heading, text)
+	})
+}
+
+// editDayNote changes the day note for date with edit, creating the note first if needed, and returns
+// the note's path.
+func editDayNote(cfg *config.Config, date time.Time, edit func(string) string) (string, error) {
+	notePath, err := dayFilePath(cfg, date)
+	if err != nil {
+		return "", err
+	}
+	created := false
+	create := func() error {
+		if _, err := os.Stat(notePath); err == nil || !os.IsNotExist(err) {
+			return err
+		}
+		dayArgs, err := linkedDayArgs(cfg, date)
+		if err != nil {
+			return err
+		}
+		if _, err = createDayFile(dayArgs, date); err != nil {
+			return err
+		}
+		created = true
+		return nil
+	}
+	if err := editNote(notePath, create, edit); err != nil {
+		return "", err
+	}
+	if created {
+		// Outside the lock on the note's folder, which the note before it may share
+		if err := linkDayNeighbours(cfg, date); err != nil {
+			fmt.Fprintln(os.Stderr, "Warning: linking the notes either side:", err)
+		}
+	}
+	return notePath, nil
+}
+
+// captureBullet writes the text as a bullet starting with the time, indenting any further lines under it.
+func captureBullet(stamp string, text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " \t\r")
+		if i > 0 && lines[i] != "" {
+			lines[i] = "  " + lines[i]
+		}
+	}
+	return fmt.Sprintf("- %s %s", stamp, strings.Join(lines, "\n"))
+}
+
+// editNote changes the note with edit while holding the lock on the note's folder, so captures from
+// two shells at once both land. create, when given, makes the note first if it doesn't exist.
+func editNote(notePath string, create func() error, edit func(string) string) error {
+	if err := os.MkdirAll(filepath.Dir(notePath), 0755); err != nil {
+		return err
+	}
+	lock, err := filelock.Acquire(filepath.Dir(notePath))
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	if create != nil {
+		if err := create(); err != nil {
+			return err
+		}
+	}
+	info, err := os.Stat(notePath)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(notePath, []byte(edit(string(content))), info.Mode().Perm())
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gnote/clock"
+	"gnote/config"
+)
+
+func TestCapture(t *testing.T) {
+	tempDir := t.TempDir()
+	mockConfig := MockConfigReader{Config: config.Config{VaultPath: tempDir, DayPath: "days", ProjectsPath: "projects", Timezone: "UTC"}}
+	config.ReadConfigMock = mockConfig.ReadConfig
+	defer func() { config.ReadConfigMock = nil }()
+	cfg := &mockConfig.Config
+	now := clock.Fixed(time.Date(2024, time.March, 4, 9, 30, 0, 0, time.UTC))
+
+	// Two shells capturing at once both land, and the day note is created once
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := capture(cfg, now, "", fmt.Sprintf("thought %d", i)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	note, err := os.ReadFile(filepath.Join(tempDir, "days", "2024_Q1", "3-4-2024.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(note), "# Monday, 4 March 2024") || strings.Count(string(note), "## Inbox\n") != 1 {
+		t.Errorf("Expected the day note with one Inbox section, but got:\n%s", note)
+	}
+	for i := 0; i < 10; i++ {
+		if !strings.Contains(string(note), fmt.Sprintf("- 09:30 thought %d\n", i)) {
+			t.Errorf("Expected thought %d to be captured, but got:\n%s", i, note)
+		}
+	}
+
+	projectPath := filepath.Join(tempDir, "projects", "PROJ-1")
+	if err := os.MkdirAll(projectPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(projectNotePath(projectPath), []byte("# PROJ-1\n\n## Inbox\n\n- older\n\n## Notes\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := capture(cfg, now, "PROJ-1", "first line\nsecond line\n"); err != nil {
+		t.Fatalf("capture returned an error: %v", err)
+	}
+	note, err = os.ReadFile(projectNotePath(projectPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "# PROJ-1\n\n## Inbox\n\n- older\n- 2024-03-04 09:30 first line\n  second line\n\n## Notes\n"; string(note) != want {
+		t.Errorf("Expected %q, but got %q", want, note)
+	}
+}
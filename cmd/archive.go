@@ -3,19 +3,21 @@ package cmd
 import (
 	"fmt"
 	"gnote/config"
+	"gnote/period"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
-	"github.com/charmbracelet/huh"
 	"github.com/spf13/cobra"
 )
 
 // archiveCmd represents the archive command
 var archiveCmd = &cobra.Command{
 	Use:   "archive",
-	Short: "Archive a project",
-	Long:  `Moves a project folder from the projects directory to the archive directory, organized by quarter.`,
+	Short: "Archive projects",
+	Long: `Moves project folders from the projects directory to the archive directory, organized by quarter,
+or by the period set with period_folders.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg, err := config.ReadConfig()
 		if err != nil {
@@ -26,11 +28,15 @@ var archiveCmd = &cobra.Command{
 		projectsPath := filepath.Join(cfg.VaultPath, cfg.ProjectsPath)
 		archivePath := filepath.Join(cfg.VaultPath, cfg.ArchivesPath)
 
-		// Get the current year and quarter
+		calendar, bucket, err := periodFolders(cfg)
+		if err != nil {
+			fmt.Println("Error reading period settings:", err)
+			return
+		}
+
+		// Get the current quarter, or whichever period archives are filed by
 		timeNow := time.Now()
-		year := timeNow.Year()
-		quarter := getQuarter(timeNow)
-		quarterFolder := fmt.Sprintf("%d_Q%d", year, quarter)
+		quarterFolder := calendar.Folder(bucket, timeNow)
 
 		// Create the quarter folder in the archive path
 		quarterArchivePath := filepath.Join(archivePath, quarterFolder)
@@ -55,33 +61,26 @@ var archiveCmd = &cobra.Command{
 		}
 
 		// User selection
-		var selectedFolder string
-		form := huh.NewForm(
-			huh.NewGroup( // Wrap the select in a group
-				huh.NewSelect[string]().
-					Title("Select project to archive:").
-					Options(generateHuhOptions(projectFolders)...).
-					Value(&selectedFolder),
-			),
-		)
-		err = form.Run()
-
+		selected, err := Picker{
+			Title: "Select projects to archive:",
+			Items: projectPickerItems(projectsPath, projectFolders),
+			Multi: true,
+		}.Run()
 		if err != nil {
 			fmt.Println("Error during selection:", err)
 			return
 		}
 
-		// Move folder
-		sourcePath := filepath.Join(projectsPath, selectedFolder)
-		destPath := filepath.Join(quarterArchivePath, selectedFolder) // Use quarterArchivePath
+		for _, item := range selected {
+			selectedFolder := item.Value
 
-		err = os.Rename(sourcePath, destPath)
-		if err != nil {
-			fmt.Printf("Error moving project '%s': %v\n", selectedFolder, err)
-			return
-		}
+			if err := archiveProject(projectsPath, quarterArchivePath, selectedFolder, timeNow); err != nil {
+				fmt.Printf("Error archiving project '%s': %v\n", selectedFolder, err)
+				continue
+			}
 
-		fmt.Printf("Project '%s' archived successfully to '%s'\n", selectedFolder, quarterArchivePath)
+			fmt.Printf("Project '%s' archived successfully to '%s'\n", selectedFolder, quarterArchivePath)
+		}
 	},
 }
 
@@ -89,6 +88,33 @@ func init() {
 	rootCmd.AddCommand(archiveCmd)
 }
 
+// archiveProject moves a project folder into the period's archive folder and records it as completed.
+func archiveProject(projectsPath string, periodArchivePath string, folder string, completed time.Time) error {
+	sourcePath := filepath.Join(projectsPath, folder)
+	destPath := filepath.Join(periodArchivePath, folder)
+	if err := os.Rename(sourcePath, destPath); err != nil {
+		return err
+	}
+	// Only once it's archived, so a project that couldn't be moved isn't counted as completed
+	if err := markCompleted(destPath, completed); err != nil {
+		return fmt.Errorf("recording completion: %w", err)
+	}
+	return nil
+}
+
+// periodFolders is how notes are filed by period, from the fiscal_year_start_month and period_folders settings.
+func periodFolders(cfg *config.Config) (period.Calendar, period.Bucket, error) {
+	calendar, err := period.NewCalendar(cfg.FiscalYearStartMonth)
+	if err != nil {
+		return period.Calendar{}, "", err
+	}
+	bucket, err := period.ParseBucket(cfg.PeriodFolders)
+	if err != nil {
+		return period.Calendar{}, "", err
+	}
+	return calendar, bucket, nil
+}
+
 func listProjectFolders(projectsPath string) ([]string, error) {
 	var folders []string
 	files, err := os.ReadDir(projectsPath)
@@ -97,7 +123,8 @@ func listProjectFolders(projectsPath string) ([]string, error) {
 	}
 
 	for _, file := range files {
-		if file.IsDir() {
+		// Hidden folders include projects that are still being created
+		if file.IsDir() && !strings.HasPrefix(file.Name(), ".") {
 			folders = append(folders, file.Name())
 		}
 	}
@@ -105,10 +132,36 @@ func listProjectFolders(projectsPath string) ([]string, error) {
 	return folders, nil
 }
 
-func generateHuhOptions(folders []string) []huh.Option[string] {
-	options := make([]huh.Option[string], len(folders))
+// listArchivedProjects returns the paths of every project folder inside the archive's period folders.
+func listArchivedProjects(archivePath string) ([]string, error) {
+	periods, err := listProjectFolders(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []string
+	for _, periodFolder := range periods {
+		periodPath := filepath.Join(archivePath, periodFolder)
+		folders, err := listProjectFolders(periodPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, folder := range folders {
+			projects = append(projects, filepath.Join(periodPath, folder))
+		}
+	}
+	return projects, nil
+}
+
+// projectPickerItems offers project folders in the picker, previewing their description notes.
+func projectPickerItems(projectsPath string, folders []string) []PickerItem {
+	items := make([]PickerItem, len(folders))
 	for i, folder := range folders {
-		options[i] = huh.NewOption(folder, folder)
+		items[i] = PickerItem{
+			Label:   folder,
+			Preview: projectNotePath(filepath.Join(projectsPath, folder)),
+			Value:   folder,
+		}
 	}
-	return options
+	return items
 }
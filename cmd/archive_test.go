@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMarkCompleted(t *testing.T) {
+	testCases := []struct {
+		name     string
+		note     string
+		expected string
+	}{
+		{
+			name:     "no front matter",
+			note:     "# PROJ-1\n",
+			expected: "---\ncompleted: 2024-04-08\n---\n# PROJ-1\n",
+		},
+		{
+			name:     "started",
+			note:     "---\nstarted: 2024-04-01\n---\n# PROJ-1\n",
+			expected: "---\nstarted: 2024-04-01\ncompleted: 2024-04-08\n---\n# PROJ-1\n",
+		},
+		{
+			name:     "already completed",
+			note:     "---\ncompleted: 2024-04-05\n---\n# PROJ-1\n",
+			expected: "---\ncompleted: 2024-04-05\n---\n# PROJ-1\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			projectPath := filepath.Join(t.TempDir(), "PROJ-1")
+			writeNotes(t, projectPath, map[string]string{"PROJ-1.md": tc.note})
+
+			if err := markCompleted(projectPath, time.Date(2024, time.April, 8, 17, 0, 0, 0, time.Local)); err != nil {
+				t.Fatalf("markCompleted returned an error: %v", err)
+			}
+			content, err := os.ReadFile(projectNotePath(projectPath))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, content)
+			}
+		})
+	}
+}
+
+func TestMarkCompletedWithoutNote(t *testing.T) {
+	projectPath := t.TempDir()
+	if err := markCompleted(projectPath, time.Now()); err != nil {
+		t.Fatalf("markCompleted returned an error: %v", err)
+	}
+	if _, err := os.Stat(projectNotePath(projectPath)); !os.IsNotExist(err) {
+		t.Errorf("Expected no description note to be created")
+	}
+}
+
+func TestArchiveProject(t *testing.T) {
+	root := t.TempDir()
+	projectsPath := filepath.Join(root, "projects")
+	quarterPath := filepath.Join(root, "archives", "2024_Q2")
+	writeNotes(t, projectsPath, map[string]string{"PROJ-1/PROJ-1.md": "# PROJ-1\n"})
+	if err := os.MkdirAll(quarterPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := archiveProject(projectsPath, quarterPath, "PROJ-1", time.Date(2024, time.April, 8, 17, 0, 0, 0, time.Local)); err != nil {
+		t.Fatalf("archiveProject returned an error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(projectsPath, "PROJ-1")); !os.IsNotExist(err) {
+		t.Errorf("Expected PROJ-1 to be gone from the projects folder")
+	}
+	completed, ok := projectCompleted(filepath.Join(quarterPath, "PROJ-1"))
+	if !ok || !completed.Equal(time.Date(2024, time.April, 8, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("Expected the archived project to be completed on 2024-04-08, got %v", completed)
+	}
+}
+
+func TestArchiveProjectMoveFails(t *testing.T) {
+	root := t.TempDir()
+	projectsPath := filepath.Join(root, "projects")
+	quarterPath := filepath.Join(root, "archives", "2024_Q2")
+	writeNotes(t, projectsPath, map[string]string{"PROJ-1/PROJ-1.md": "# PROJ-1\n"})
+	// An archived project of the same name is in the way
+	writeNotes(t, quarterPath, map[string]string{"PROJ-1/PROJ-1.md": "# Archived PROJ-1\n"})
+
+	if err := archiveProject(projectsPath, quarterPath, "PROJ-1", time.Now()); err == nil {
+		t.Fatal("Expected an error when the archive already has the project")
+	}
+	// Nothing is marked completed, in either place, when the project couldn't be moved
+	for _, projectPath := range []string{filepath.Join(projectsPath, "PROJ-1"), filepath.Join(quarterPath, "PROJ-1")} {
+		if _, ok := projectCompleted(projectPath); ok {
+			t.Errorf("Expected %s not to be marked completed", projectPath)
+		}
+	}
+}
+
+func TestUnarchiveProject(t *testing.T) {
+	root := t.TempDir()
+	projectsPath := filepath.Join(root, "projects")
+	archivedPath := filepath.Join(root, "archives", "2024_Q2", "PROJ-1")
+	writeNotes(t, archivedPath, map[string]string{"PROJ-1.md": "# PROJ-1\n"})
+	if err := os.MkdirAll(projectsPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := unarchiveProject(projectsPath, archivedPath); err != nil {
+		t.Fatalf("unarchiveProject returned an error: %v", err)
+	}
+	content, err := os.ReadFile(projectNotePath(filepath.Join(projectsPath, "PROJ-1")))
+	if err != nil || string(content) != "# PROJ-1\n" {
+		t.Errorf("Expected PROJ-1 back in the projects folder, got %q (%v)", content, err)
+	}
+	if _, err := os.Stat(archivedPath); !os.IsNotExist(err) {
+		t.Errorf("Expected PROJ-1 to be gone from the archive")
+	}
+}
+
+func TestUnarchiveProjectExisting(t *testing.T) {
+	testCases := []struct {
+		name     string
+		existing map[string]string
+	}{
+		{"project", map[string]string{"PROJ-1/PROJ-1.md": "# New PROJ-1\n"}},
+		{"empty folder", map[string]string{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			projectsPath := filepath.Join(root, "projects")
+			archivedPath := filepath.Join(root, "archives", "2024_Q2", "PROJ-1")
+			writeNotes(t, archivedPath, map[string]string{"PROJ-1.md": "# PROJ-1\n"})
+			if err := os.MkdirAll(filepath.Join(projectsPath, "PROJ-1"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			writeNotes(t, projectsPath, tc.existing)
+
+			if err := unarchiveProject(projectsPath, archivedPath); err == nil {
+				t.Fatal("Expected an error when the project already exists")
+			}
+			content, err := os.ReadFile(projectNotePath(archivedPath))
+			if err != nil || string(content) != "# PROJ-1\n" {
+				t.Errorf("Expected the archived project to stay put, got %q (%v)", content, err)
+			}
+			for name, want := range tc.existing {
+				if content, err := os.ReadFile(filepath.Join(projectsPath, name)); err != nil || string(content) != want {
+					t.Errorf("Expected %s to be left alone, got %q (%v)", name, content, err)
+				}
+			}
+		})
+	}
+}
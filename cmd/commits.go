@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gnote/config"
+)
+
+type Commit struct {
+	Repo    string
+	Hash    string
+	Branch  string
+	Subject string
+}
+
+// CommitGroup is a set of commits that belong to the same ticket.
+// Commits without a recognisable ticket key end up in a group with an empty Ticket.
+type CommitGroup struct {
+	Ticket  string
+	Commits []Commit
+}
+
+var ticketKeyPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9]+-[0-9]+\b`)
+
+// ticketKey looks for a ticket key like PROJ-123 in the branch first, then the commit message.
+func ticketKey(c Commit) string {
+	if key := ticketKeyPattern.FindString(c.Branch); key != "" {
+		return key
+	}
+	return ticketKeyPattern.FindString(c.Subject)
+}
+
+func groupCommitsByTicket(commits []Commit) []CommitGroup {
+	indexByTicket := map[string]int{}
+	var groups []CommitGroup
+	for _, c := range commits {
+		key := ticketKey(c)
+		i, ok := indexByTicket[key]
+		if !ok {
+			i = len(groups)
+			indexByTicket[key] = i
+			groups = append(groups, CommitGroup{Ticket: key})
+		}
+		groups[i].Commits = append(groups[i].Commits, c)
+	}
+
+	// Tickets alphabetically, commits that couldn't be matched to a ticket last
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Ticket == "" || groups[j].Ticket == "" {
+			return groups[j].Ticket == ""
+		}
+		return groups[i].Ticket < groups[j].Ticket
+	})
+	return groups
+}
+
+// collectCommits gathers the author's commits from every configured repository made between since and until.
+func collectCommits(cfg *config.Config, since time.Time, until time.Time) ([]CommitGroup, error) {
+	var commits []Commit
+	for _, repo := range cfg.Repositories {
+		repoCommits, err := repoCommits(expandHome(repo), cfg.GitAuthor, since, until)
+		if err != nil {
+			return nil, fmt.Errorf("reading commits from %s: %w", repo, err)
+		}
+		commits = append(commits, repoCommits...)
+	}
+	return groupCommitsByTicket(commits), nil
+}
+
+func repoCommits(repo string, author string, since time.Time, until time.Time) ([]Commit, error) {
+	if author == "" {
+		out, err := exec.Command("git", "-C", repo, "config", "user.email").Output()
+		if err != nil {
+			return nil, fmt.Errorf("no git_author configured and user.email is not set: %w", err)
+		}
+		author = strings.TrimSpace(string(out))
+	}
+
+	// --source makes %S print the ref each commit was reached from, which is how we learn the branch
+	out, err := exec.Command("git", "-C", repo, "log", "--all", "--source", "--no-merges", "--reverse",
+		"--since="+since.Format(time.RFC3339), "--until="+until.Format(time.RFC3339), "--author="+author,
+		"--format=%h%x1f%S%x1f%s").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, line := range bytes.Split(bytes.TrimSpace(out), []byte("\n")) {
+		fields := strings.SplitN(string(line), "\x1f", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		commits = append(commits, Commit{
+			Repo:    filepath.Base(repo),
+			Hash:    fields[0],
+			Branch:  shortBranchName(fields[1]),
+			Subject: fields[2],
+		})
+	}
+	return commits, nil
+}
+
+func shortBranchName(ref string) string {
+	for _, prefix := range []string{"refs/heads/", "refs/remotes/origin/", "refs/remotes/"} {
+		if strings.HasPrefix(ref, prefix) {
+			return strings.TrimPrefix(ref, prefix)
+		}
+	}
+	return ref
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+	return path
+}
+
+// commitsSince works out where the commit log should start: the day after the previous day note, or today.
+func commitsSince(cfg *config.Config, timeNow time.Time) (time.Time, error) {
+	day, err := dayBoundary(cfg)
+	if err != nil {
+		return time.Time{}, err
+	}
+	since := day.Start(timeNow)
+	index, err := loadDayNotes(cfg, day.Location)
+	if err != nil {
+		return since, err
+	}
+	if previous, found := index.Prev(timeNow); found {
+		since = day.Start(previous.Date.AddDate(0, 0, 1))
+	}
+	return since, nil
+}
+
+// writeCommitsSection puts the commit groups under the day note's "## Commits" heading,
+// replacing whatever was logged there before.
+func writeCommitsSection(filePath string, groups []CommitGroup) error {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	if err := newDayTemplate().ExecuteTemplate(&body, "commits", groups); err != nil {
+		return err
+	}
+
+	updated := upsertSection(string(content), "## Commits", body.String())
+	return os.WriteFile(filePath, []byte(updated), 0644)
+}
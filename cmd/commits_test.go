@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestGroupCommitsByTicket(t *testing.T) {
+	commits := []Commit{
+		{Repo: "api", Hash: "a1", Branch: "gb/PROJ-12-login", Subject: "Add login form"},
+		{Repo: "api", Hash: "b2", Branch: "main", Subject: "Fix typo"},
+		{Repo: "web", Hash: "c3", Branch: "main", Subject: "ABC-7 bump deps"},
+		{Repo: "api", Hash: "d4", Branch: "gb/PROJ-12-login", Subject: "Validate ABC-7 input"},
+	}
+
+	groups := groupCommitsByTicket(commits)
+
+	expected := []struct {
+		ticket string
+		hashes []string
+	}{
+		{"ABC-7", []string{"c3"}},
+		{"PROJ-12", []string{"a1", "d4"}},
+		{"", []string{"b2"}},
+	}
+
+	if len(groups) != len(expected) {
+		t.Fatalf("Expected %d groups, but got %d: %+v", len(expected), len(groups), groups)
+	}
+	for i, want := range expected {
+		if groups[i].Ticket != want.ticket {
+			t.Errorf("Expected group %d to be %q, but got %q", i, want.ticket, groups[i].Ticket)
+		}
+		if len(groups[i].Commits) != len(want.hashes) {
+			t.Fatalf("Expected %d commits for %q, but got %d", len(want.hashes), want.ticket, len(groups[i].Commits))
+		}
+		for j, hash := range want.hashes {
+			if groups[i].Commits[j].Hash != hash {
+				t.Errorf("Expected commit %d of %q to be %s, but got %s", j, want.ticket, hash, groups[i].Commits[j].Hash)
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayouts are the absolute dates parseDayExpression understands, including the day note file names.
+var dateLayouts = []string{"2006-01-02", "2006/01/02", "1-2-2006", "2 January 2006", "January 2 2006"}
+
+// parseDayExpression turns what someone would type for a day into that day, relative to now:
+// today, yesterday, tomorrow, -1, +2, friday, last friday, next monday, or a date like 2024-03-05.
+// Relative days keep now's time of day.
+func parseDayExpression(expr string, now time.Time) (time.Time, error) {
+	expr = strings.ToLower(strings.TrimSpace(expr))
+	switch expr {
+	case "", "today":
+		return now, nil
+	case "yesterday":
+		return now.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return now.AddDate(0, 0, 1), nil
+	}
+
+	if offset, err := strconv.Atoi(expr); err == nil && (expr[0] == '-' || expr[0] == '+') {
+		return now.AddDate(0, 0, offset), nil
+	}
+
+	words := strings.Fields(expr)
+	if weekday, ok := parseWeekday(words[len(words)-1]); ok && len(words) <= 2 {
+		direction := "this"
+		if len(words) == 2 {
+			direction = words[0]
+		}
+		diff := int(weekday - now.Weekday())
+		switch direction {
+		case "last":
+			// Strictly before today, so "last friday" on a Friday is a week ago
+			if diff >= 0 {
+				diff -= 7
+			}
+		case "next":
+			if diff <= 0 {
+				diff += 7
+			}
+		case "this":
+			// The most recent one, which is today on that weekday
+			if diff > 0 {
+				diff -= 7
+			}
+		default:
+			return time.Time{}, fmt.Errorf("can't understand the day %q", expr)
+		}
+		return now.AddDate(0, 0, diff), nil
+	}
+
+	for _, layout := range dateLayouts {
+		if date, err := time.ParseInLocation(layout, expr, now.Location()); err == nil {
+			return time.Date(date.Year(), date.Month(), date.Day(), now.Hour(), now.Minute(), now.Second(), 0, now.Location()), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("can't understand the day %q, try yesterday, -1, last friday, next monday or 2024-03-05", expr)
+}
+
+func parseWeekday(word string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if word == name || word == name[:3] {
+			return day, true
+		}
+	}
+	return 0, false
+}
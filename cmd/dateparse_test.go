@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDayExpression(t *testing.T) {
+	// Wednesday
+	now := time.Date(2024, time.March, 13, 9, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		expr     string
+		expected string
+	}{
+		{"", "2024-03-13"},
+		{"today", "2024-03-13"},
+		{"yesterday", "2024-03-12"},
+		{"Tomorrow", "2024-03-14"},
+		{"-1", "2024-03-12"},
+		{"+2", "2024-03-15"},
+		{"friday", "2024-03-08"},
+		{"wednesday", "2024-03-13"},
+		{"last friday", "2024-03-08"},
+		{"last wednesday", "2024-03-06"},
+		{"next monday", "2024-03-18"},
+		{"next wed", "2024-03-20"},
+		{"2024-03-05", "2024-03-05"},
+		{"3-5-2024", "2024-03-05"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			got, err := parseDayExpression(tc.expr, now)
+			if err != nil {
+				t.Fatalf("parseDayExpression returned an error: %v", err)
+			}
+			if got.Format("2006-01-02") != tc.expected {
+				t.Errorf("Expected %s, but got %s", tc.expected, got.Format("2006-01-02"))
+			}
+		})
+	}
+
+	for _, expr := range []string{"someday", "1", "after friday", "2024-13-01"} {
+		if _, err := parseDayExpression(expr, now); err == nil {
+			t.Errorf("Expected %q to be rejected", expr)
+		}
+	}
+}
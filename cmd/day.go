@@ -4,20 +4,30 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"text/template"
 	"time"
 
+	"gnote/checklist"
+	"gnote/clock"
 	"gnote/config"
+	"gnote/daynote"
 	"os/exec"
 
 	"github.com/spf13/cobra"
 )
 
 type DayArgs struct {
-	Day                  string
-	ShowTimesheet        bool
-	ShowWorkingWednesday bool
-	ShowExpenseTodo      bool
+	Day string
+	// Checklist is the checklist items due on the day, by section
+	Checklist []checklist.Section
+	Commits   []CommitGroup
+	// Meetings are the day's meetings from the configured calendars
+	Meetings []Meeting
+	// PrevNote and NextNote are the names of the neighbouring day notes, for navigation links
+	PrevNote string
+	NextNote string
 }
 
 type Editor interface {
@@ -42,38 +52,260 @@ func (n NvimEditor) OpenFile(filePath string) error {
 	return nil
 }
 
+var (
+	logCommits bool
+	noCreate   bool
+)
+
 var dayCmd = &cobra.Command{
-	Use:   "day",
+	Use:   "day [date]",
 	Short: "Create a new DevLog for the current day.",
+	Long: `Opens the DevLog for today, creating it if needed, or for another day given as
+'yesterday', 'tomorrow', '-1', '+2', 'friday', 'last friday', 'next monday' or '2024-03-05'.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		timeNow := time.Now()
-		dayArgs := buildDayArgs(timeNow)
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			fmt.Printf("Failed to read config: %s\n", err)
+			os.Exit(1)
+		}
 
-		filePath, err := createDayFile(dayArgs, timeNow)
+		now, err := today(cfg, clock.System)
 		if err != nil {
-			fmt.Printf("Failed to create day file: %s\n", err)
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		timeNow, err := parseDayExpression(strings.Join(args, " "), now)
+		if err != nil {
+			fmt.Println(err)
 			os.Exit(1)
 		}
 
-		editor := NvimEditor{}
-		if err := editor.OpenFile(filePath); err != nil {
-			fmt.Printf("Failed to open file in editor: %s\n", err)
+		if noCreate {
+			filePath, err := dayFilePath(cfg, timeNow)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if _, err := os.Stat(filePath); err != nil {
+				fmt.Printf("No day note for %s\n", timeNow.Format("Monday, 2 January 2006"))
+				os.Exit(1)
+			}
+			openDayFile(cfg, filePath)
+			return
+		}
+
+		dayArgs, err := linkedDayArgs(cfg, timeNow)
+		if err != nil {
+			fmt.Printf("Failed to read day notes: %s\n", err)
 			os.Exit(1)
 		}
+
+		if logCommits {
+			commits, err := dayCommits(cfg, timeNow)
+			if err != nil {
+				fmt.Printf("Failed to read commits: %s\n", err)
+				os.Exit(1)
+			}
+			dayArgs.Commits = commits
+		}
+
+		filePath, err := dayFilePath(cfg, timeNow)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		_, statErr := os.Stat(filePath)
+		if filePath, err = createDayFile(dayArgs, timeNow); err != nil {
+			fmt.Printf("Failed to create day file: %s\n", err)
+			os.Exit(1)
+		}
+		if os.IsNotExist(statErr) {
+			if err := linkDayNeighbours(cfg, timeNow); err != nil {
+				fmt.Fprintln(os.Stderr, "Warning: linking the notes either side:", err)
+			}
+		}
+
+		if logCommits {
+			// The note may have been created earlier in the day, so make sure the section is up to date
+			if err := writeCommitsSection(filePath, dayArgs.Commits); err != nil {
+				fmt.Printf("Failed to log commits: %s\n", err)
+				os.Exit(1)
+			}
+		}
+
+		openDayFile(cfg, filePath)
 	},
 }
 
-func buildDayArgs(timeNow time.Time) DayArgs {
+func openDayFile(cfg *config.Config, filePath string) {
+	editor := newEditor(cfg)
+	if err := editor.OpenFile(filePath); err != nil {
+		fmt.Printf("Failed to open file in editor: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// dayCommits collects the commits for the day's note, from the day after the previous note until the end of the day.
+func dayCommits(cfg *config.Config, timeNow time.Time) ([]CommitGroup, error) {
+	day, err := dayBoundary(cfg)
+	if err != nil {
+		return nil, err
+	}
+	since, err := commitsSince(cfg, timeNow)
+	if err != nil {
+		return nil, err
+	}
+	return collectCommits(cfg, since, day.End(timeNow))
+}
+
+// dayBoundary is how the clock maps onto days for day notes, from the timezone and day_starts_at settings.
+func dayBoundary(cfg *config.Config) (clock.Day, error) {
+	return clock.NewDay(cfg.Timezone, cfg.DayStartsAt)
+}
+
+// today is the day it is on c as far as day notes go: in the configured timezone,
+// and still yesterday until day_starts_at. The quarter, month end and file name all follow from it.
+func today(cfg *config.Config, c clock.Clock) (time.Time, error) {
+	day, err := dayBoundary(cfg)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return day.Today(c), nil
+}
+
+func buildDayArgs(timeNow time.Time, items []checklist.Item) DayArgs {
 	formattedDay := fmt.Sprintf("%s, %d %s %d\n", timeNow.Weekday(), timeNow.Day(), timeNow.Month().String(), timeNow.Year())
-	showTimesheet := timeNow.Weekday() == time.Friday
-	showWorkingWednesday := timeNow.Weekday() == time.Wednesday
-	showExpenseTodo := isLastWeekdayOfMonth(timeNow)
 	return DayArgs{
-		Day:                  formattedDay,
-		ShowTimesheet:        showTimesheet,
-		ShowWorkingWednesday: showWorkingWednesday,
-		ShowExpenseTodo:      showExpenseTodo,
+		Day:       formattedDay,
+		Checklist: checklist.Due(items, timeNow),
+	}
+}
+
+// checklistItems are the configured checklist items, or the default morning checklist.
+func checklistItems(cfg *config.Config) ([]checklist.Item, error) {
+	if len(cfg.Checklist) == 0 {
+		return checklist.Default, nil
+	}
+	var items []checklist.Item
+	for _, item := range cfg.Checklist {
+		schedule, err := checklist.ParseSchedule(item.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("checklist item %q: %w", item.Label, err)
+		}
+		items = append(items, checklist.Item{ID: item.ID, Label: item.Label, Schedule: schedule, Section: item.Section})
+	}
+	return items, nil
+}
+
+// linkedDayArgs is buildDayArgs along with links to the day notes either side of timeNow, and a link to
+// the week's timesheet export from the time sheet item.
+func linkedDayArgs(cfg *config.Config, timeNow time.Time) (DayArgs, error) {
+	items, err := checklistItems(cfg)
+	if err != nil {
+		return DayArgs{}, err
+	}
+	dayArgs := buildDayArgs(timeNow, items)
+	index, err := loadDayNotes(cfg, timeNow.Location())
+	if err != nil {
+		return DayArgs{}, err
 	}
+	if prev, ok := index.Prev(timeNow); ok {
+		dayArgs.PrevNote = prev.Name()
+	}
+	if next, ok := index.Next(timeNow); ok {
+		dayArgs.NextNote = next.Name()
+	}
+	if item := checklist.Find(dayArgs.Checklist, "timesheet"); item != nil && cfg.Timesheet.Format != "" {
+		monday := weekStart(timeNow)
+		item.Link = timesheetFileName(monday, monday.AddDate(0, 0, 6), cfg.Timesheet.Format)
+	}
+	return dayArgs, nil
+}
+
+// Nav is the line of links to the neighbouring day notes under the title, empty when there are none.
+func (a DayArgs) Nav() string {
+	return dayNav(a.PrevNote, a.NextNote)
+}
+
+func dayNav(prev, next string) string {
+	var links []string
+	if prev != "" {
+		links = append(links, fmt.Sprintf("← [[%s]]", prev))
+	}
+	if next != "" {
+		links = append(links, fmt.Sprintf("[[%s]] →", next))
+	}
+	return strings.Join(links, " | ")
+}
+
+var dayNavPattern = regexp.MustCompile(`^(?:← \[\[([^\]]+)\]\])?(?: \| )?(?:\[\[([^\]]+)\]\] →)?$`)
+
+// setDayNav points the links under a day note's title at prev and next, keeping the current link for
+// either one left empty. Notes without links get them where new notes have them, a blank line under the title.
+func setDayNav(content string, prev, next string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## ") {
+			break
+		}
+		line = strings.TrimRight(line, "\r")
+		match := dayNavPattern.FindStringSubmatch(line)
+		if match == nil || !strings.ContainsAny(line, "←→") {
+			continue
+		}
+		if prev == "" {
+			prev = match[1]
+		}
+		if next == "" {
+			next = match[2]
+		}
+		lines[i] = dayNav(prev, next) + strings.TrimPrefix(lines[i], line)
+		return strings.Join(lines, "\n")
+	}
+
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## ") {
+			break
+		}
+		if !strings.HasPrefix(line, "# ") {
+			continue
+		}
+		rest := i + 1
+		for rest < len(lines) && strings.TrimRight(lines[rest], "\r") == "" {
+			rest++
+		}
+		linked := append(append([]string{}, lines[:i+1]...), "", dayNav(prev, next), "")
+		return strings.Join(append(linked, lines[rest:]...), "\n")
+	}
+	return content
+}
+
+// linkDayNeighbours adds links to the new day note for date to the notes either side of it, so the
+// links between day notes go both ways. It takes the lock on each neighbour's folder in turn, so it must
+// be called without holding one.
+func linkDayNeighbours(cfg *config.Config, date time.Time) error {
+	index, err := loadDayNotes(cfg, date.Location())
+	if err != nil {
+		return err
+	}
+	note, ok := index.Find(date)
+	if !ok {
+		return nil
+	}
+	if prev, ok := index.Prev(date); ok {
+		err := editNote(prev.Path, nil, func(content string) string {
+			return setDayNav(content, "", note.Name())
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if next, ok := index.Next(date); ok {
+		return editNote(next.Path, nil, func(content string) string {
+			return setDayNav(content, note.Name(), "")
+		})
+	}
+	return nil
 }
 
 func createDayFile(args DayArgs, timeNow time.Time) (string, error) {
@@ -82,19 +314,16 @@ func createDayFile(args DayArgs, timeNow time.Time) (string, error) {
 		return "", err
 	}
 
-	year := timeNow.Year()
-	quarter := getQuarter(timeNow)
-	quarterFolder := fmt.Sprintf("%d_Q%d", year, quarter)
-
-	folderPath := filepath.Join(cfg.VaultPath, cfg.DayPath, quarterFolder)
+	filePath, err := dayFilePath(cfg, timeNow)
+	if err != nil {
+		return "", err
+	}
 
 	// Create the folder if it doesn't exist
-	if err := os.MkdirAll(folderPath, 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
 		return "", err
 	}
 
-	filePath := filepath.Join(folderPath, fmt.Sprintf("%d-%d-%d.md", timeNow.Month(), timeNow.Day(), timeNow.Year()))
-
 	_, err = os.Stat(filePath)
 	if err == nil {
 		// File exists, don't overwrite
@@ -106,6 +335,13 @@ func createDayFile(args DayArgs, timeNow time.Time) (string, error) {
 		return "", err
 	}
 
+	// Only a new note needs the calendars read, and a calendar that can't be read leaves its meetings out
+	var problems []error
+	args.Meetings, problems = dayMeetings(cfg, timeNow)
+	for _, problem := range problems {
+		fmt.Fprintln(os.Stderr, "Warning:", problem)
+	}
+
 	file, err := os.Create(filePath)
 	if err != nil {
 		return "", err
@@ -121,71 +357,104 @@ func createDayFile(args DayArgs, timeNow time.Time) (string, error) {
 	return filePath, nil
 }
 
-func getQuarter(date time.Time) int {
-	month := date.Month()
-	switch {
-	case month >= time.January && month <= time.March:
-		return 1
-	case month >= time.April && month <= time.June:
-		return 2
-	case month >= time.July && month <= time.September:
-		return 3
-	case month >= time.October && month <= time.December:
-		return 4
-	default:
-		return 0 // Should never happen
-	}
-}
-
-func isLastWeekdayOfMonth(date time.Time) bool {
-	// Get the last day of the month
-	// Using the zeroth day trick to get the last day of month
-	lastDay := time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, date.Location())
-
-	// Check if the given date is one of the last weekdays of the month
-	for i := 0; i < 7; i++ {
-		currentDay := lastDay.AddDate(0, 0, -i)
-		// Only consider weekdays (Monday to Friday)
-		if currentDay.Weekday() >= time.Monday && currentDay.Weekday() <= time.Friday {
-			if currentDay.Weekday() == date.Weekday() &&
-				currentDay.Year() == date.Year() &&
-				currentDay.Month() == date.Month() &&
-				currentDay.Day() == date.Day() {
-				return true
-			}
-		}
+// dayFilePath is where the day note for the given day lives, M-D-YYYY.md in a YYYY_QN folder
+// unless day_filename_format or day_folder_format say otherwise.
+func dayFilePath(cfg *config.Config, timeNow time.Time) (string, error) {
+	layout, err := dayLayout(cfg)
+	if err != nil {
+		return "", err
 	}
+	return layout.Path(filepath.Join(cfg.VaultPath, cfg.DayPath), timeNow), nil
+}
 
-	return false
+// dayLayout is day_filename_format in day_folder_format folders, or in period folders when no folder format is set.
+func dayLayout(cfg *config.Config) (daynote.Layout, error) {
+	calendar, bucket, err := periodFolders(cfg)
+	if err != nil {
+		return daynote.Layout{}, err
+	}
+	folderFormat := cfg.DayFolderFormat
+	if folderFormat == "" {
+		folderFormat = bucket.Format()
+	}
+	return daynote.NewLayout(folderFormat, cfg.DayFilenameFormat, calendar)
+}
+
+func loadDayNotes(cfg *config.Config, loc *time.Location) (*daynote.Index, error) {
+	layout, err := dayLayout(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return daynote.Load(filepath.Join(cfg.VaultPath, cfg.DayPath), layout, loc)
 }
 
 func init() {
 	rootCmd.AddCommand(dayCmd)
+	dayCmd.Flags().BoolVar(&noCreate, "no-create", false, "Only open the note if it already exists")
+	dayCmd.Flags().BoolVar(&logCommits, "log-commits", false, "Add your commits since the previous day note to a Commits section")
 }
 
 func newDayTemplate() *template.Template {
 	const newDayTemplate = `# {{.Day}}
+{{- with .Nav }}
+{{ . }}
+{{- end }}
+{{- range .Checklist }}
+
+## {{ .Name }}
+
+{{ template "checklist" .Items }}
+{{- end }}
+
+{{- if .Meetings }}
 
-## Morning Checklist
-
-- [ ] check email
-- [ ] check calendar
-- [ ] check Slack
-- [ ] check home todo
-{{- if .ShowTimesheet }}
-- [ ] time sheet
-{{-  end }}
-{{- if .ShowWorkingWednesday}}
-- [ ] working Wednesday
-{{-  end }}
-{{- if .ShowExpenseTodo}}
-- [ ] WFH expenses in Concur
-{{-  end }}
+## Meetings
+
+{{ template "meetings" .Meetings }}
+{{- end }}
 
 ## What do you want to accomplish today?
 
 - [ ]
+{{- if .Commits }}
+
+## Commits
+
+{{ template "commits" .Commits }}
+{{- end }}
 `
 
-	return template.Must(template.New("newDayTemplate").Parse(newDayTemplate))
+	// Rendered on its own when logging commits into an existing note
+	const commitsTemplate = `
+{{- range $i, $group := . }}
+{{- if $i }}
+
+{{ end }}
+{{- if $group.Ticket }}### [[{{ $group.Ticket }}]]{{ else }}### Other{{ end }}
+{{ range $group.Commits }}
+- ` + "`{{ .Hash }}`" + ` {{ .Subject }} ({{ .Repo }}{{ if .Branch }}@{{ .Branch }}{{ end }})
+{{- end }}
+{{- else }}
+No commits.
+{{- end }}`
+
+	const checklistTemplate = `
+{{- range $i, $item := . }}
+{{- if $i }}
+{{ end }}
+{{- "" }}- [ ] {{ $item.Label }}{{ if $item.Link }} ([[{{ $item.Link }}]]){{ end }}
+{{- end }}`
+
+	const meetingsTemplate = `
+{{- range $i, $meeting := . }}
+{{- if $i }}
+{{ end }}
+{{- "" }}- {{ $meeting.Times }} {{ $meeting.Title }}
+{{- end }}`
+
+	t := template.Must(template.New("newDayTemplate").Parse(newDayTemplate))
+	template.Must(t.New("checklist").Parse(checklistTemplate))
+	template.Must(t.New("commits").Parse(commitsTemplate))
+	template.Must(t.New("meetings").Parse(meetingsTemplate))
+	return t
 }
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gnote/config"
+	"gnote/daynote"
+	"gnote/period"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	dayMigrateDryRun     bool
+	dayMigrateFromFile   string
+	dayMigrateFromFolder string
+)
+
+// dayMigrateCmd represents the day migrate command
+var dayMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Move day notes into the configured day_filename_format and day_folder_format",
+	Long: `Moves day notes from the original M-D-YYYY.md in YYYY_QN folders, or the layout given with
+--from-file and --from-folder, into the configured layout. Links to renamed notes are updated across the vault.
+Nothing is overwritten: if a note's new path is taken, nothing is moved.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			return
+		}
+
+		from, err := daynote.NewLayout(dayMigrateFromFolder, dayMigrateFromFile, period.Calendar{})
+		if err != nil {
+			fmt.Println("Error in --from-file or --from-folder:", err)
+			return
+		}
+		to, err := dayLayout(cfg)
+		if err != nil {
+			fmt.Println("Error reading the day note layout:", err)
+			return
+		}
+
+		root := filepath.Join(cfg.VaultPath, cfg.DayPath)
+		moves, err := daynote.PlanMigration(root, from, to, time.Local)
+		if err != nil {
+			fmt.Println("Error planning the migration:", err)
+			return
+		}
+		if len(moves) == 0 {
+			fmt.Println("No day notes to move")
+			return
+		}
+
+		verb := "Moved"
+		if dayMigrateDryRun {
+			verb = "Would move"
+		}
+		for _, move := range moves {
+			fmt.Printf("%s %s to %s\n", verb, relativeTo(root, move.From), relativeTo(root, move.To))
+		}
+		if dayMigrateDryRun {
+			return
+		}
+
+		if err := daynote.ApplyMigration(root, moves); err != nil {
+			fmt.Println("Error moving day notes:", err)
+			return
+		}
+		updated, err := rewriteDayNoteLinks(cfg.VaultPath, moves)
+		if err != nil {
+			fmt.Println("Error updating links:", err)
+			return
+		}
+		fmt.Printf("%d day note(s) moved, links updated in %d note(s)\n", len(moves), updated)
+	},
+}
+
+func init() {
+	dayCmd.AddCommand(dayMigrateCmd)
+	dayMigrateCmd.Flags().BoolVar(&dayMigrateDryRun, "dry-run", false, "Only list the notes that would be moved")
+	dayMigrateCmd.Flags().StringVar(&dayMigrateFromFile, "from-file", daynote.DefaultFileFormat, "The file name format the notes have now")
+	dayMigrateCmd.Flags().StringVar(&dayMigrateFromFolder, "from-folder", daynote.DefaultFolderFormat, "The folder format the notes are in now")
+}
+
+// rewriteDayNoteLinks updates links to renamed day notes in every note in the vault, returning how many changed.
+// Hidden folders like .obsidian and .git are skipped.
+func rewriteDayNoteLinks(vaultPath string, moves []daynote.Move) (int, error) {
+	updated := 0
+	err := filepath.WalkDir(vaultPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != vaultPath && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".md" {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rewritten, changed := daynote.RewriteLinks(content, moves)
+		if !changed {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, rewritten, info.Mode().Perm()); err != nil {
+			return err
+		}
+		updated++
+		return nil
+	})
+	return updated, err
+}
+
+func relativeTo(root string, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil {
+		return rel
+	}
+	return path
+}
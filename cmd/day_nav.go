@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gnote/clock"
+	"gnote/config"
+	"gnote/daynote"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	listFrom string
+	listTo   string
+)
+
+// dayPrevCmd represents the day prev command
+var dayPrevCmd = &cobra.Command{
+	Use:   "prev [date]",
+	Short: "Open the day note before today, or before the given day",
+	Run: func(cmd *cobra.Command, args []string) {
+		openNeighbourDayNote(args, (*daynote.Index).Prev, "before")
+	},
+}
+
+// dayNextCmd represents the day next command
+var dayNextCmd = &cobra.Command{
+	Use:   "next [date]",
+	Short: "Open the day note after today, or after the given day",
+	Long: `Opens the next existing day note. 'gnote day next monday' still means the note for next Monday,
+and creates it like 'gnote day' would.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 1 {
+			if _, ok := parseWeekday(strings.ToLower(args[0])); ok {
+				dayCmd.Run(dayCmd, append([]string{"next"}, args...))
+				return
+			}
+		}
+		openNeighbourDayNote(args, (*daynote.Index).Next, "after")
+	},
+}
+
+// dayListCmd represents the day list command
+var dayListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List day notes in date order",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			return
+		}
+
+		now, err := today(cfg, clock.System)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		var from, to time.Time
+		if listFrom != "" {
+			if from, err = parseDayExpression(listFrom, now); err != nil {
+				fmt.Println(err)
+				return
+			}
+		}
+		if listTo != "" {
+			if to, err = parseDayExpression(listTo, now); err != nil {
+				fmt.Println(err)
+				return
+			}
+		}
+
+		index, err := loadDayNotes(cfg, now.Location())
+		if err != nil {
+			fmt.Println("Error reading day notes:", err)
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, note := range index.Between(from, to) {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", note.Date.Format("2006-01-02"), note.Date.Weekday(), note.Path)
+		}
+		w.Flush()
+	},
+}
+
+func init() {
+	dayCmd.AddCommand(dayPrevCmd)
+	dayCmd.AddCommand(dayNextCmd)
+	dayCmd.AddCommand(dayListCmd)
+	dayListCmd.Flags().StringVar(&listFrom, "from", "", "First day to list, e.g. 2024-03-01 or 'last monday'")
+	dayListCmd.Flags().StringVar(&listTo, "to", "", "Last day to list")
+}
+
+// openNeighbourDayNote opens the note that neighbour finds next to the given day, today by default.
+func openNeighbourDayNote(args []string, neighbour func(*daynote.Index, time.Time) (daynote.Note, bool), direction string) {
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		fmt.Println("Error reading config:", err)
+		return
+	}
+	now, err := today(cfg, clock.System)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	date, err := parseDayExpression(strings.Join(args, " "), now)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	index, err := loadDayNotes(cfg, now.Location())
+	if err != nil {
+		fmt.Println("Error reading day notes:", err)
+		return
+	}
+	note, ok := neighbour(index, date)
+	if !ok {
+		fmt.Printf("No day note %s %s\n", direction, date.Format("Monday, 2 January 2006"))
+		return
+	}
+	openDayFile(cfg, note.Path)
+}
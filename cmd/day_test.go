@@ -1,87 +1,190 @@
 package cmd
 
 import (
+	"gnote/checklist"
+	"gnote/clock"
 	"gnote/config"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
 
 func TestBuildDayArgs(t *testing.T) {
+	schedule := func(s string) checklist.Schedule {
+		parsed, err := checklist.ParseSchedule(s)
+		if err != nil {
+			t.Fatalf("ParseSchedule(%q) returned an error: %v", s, err)
+		}
+		return parsed
+	}
+	configured := []checklist.Item{
+		{ID: "standup", Label: "standup notes", Schedule: schedule("weekdays")},
+		{ID: "invoice", Label: "send invoice", Schedule: schedule("day 1, last day"), Section: "Admin"},
+		{ID: "backup", Label: "check backups", Schedule: schedule("last friday"), Section: "Admin"},
+		{ID: "plants", Label: "water plants", Schedule: schedule("sat, sun"), Section: "Home"},
+	}
+
 	testCases := []struct {
-		name          string
-		date          time.Time
-		expectedDay   string
-		showTimesheet bool
-		showWednesday bool
-		showExpense   bool
+		name        string
+		date        time.Time
+		items       []checklist.Item
+		expectedDay string
+		// expected are the due items as section/id, in order
+		expected []string
 	}{
 		{
-			name:          "Friday",
-			date:          time.Date(1970, time.January, 2, 12, 0, 0, 0, time.UTC),
-			expectedDay:   "Friday, 2 January 1970\n",
-			showTimesheet: true,
-			showWednesday: false,
-			showExpense:   false,
+			name:        "Friday",
+			date:        time.Date(1970, time.January, 2, 12, 0, 0, 0, time.UTC),
+			items:       checklist.Default,
+			expectedDay: "Friday, 2 January 1970\n",
+			expected:    []string{"Morning Checklist/email", "Morning Checklist/calendar", "Morning Checklist/slack", "Morning Checklist/home-todo", "Morning Checklist/timesheet"},
+		},
+		{
+			name:        "Wednesday",
+			date:        time.Date(1970, time.January, 7, 12, 0, 0, 0, time.UTC),
+			items:       checklist.Default,
+			expectedDay: "Wednesday, 7 January 1970\n",
+			expected:    []string{"Morning Checklist/email", "Morning Checklist/calendar", "Morning Checklist/slack", "Morning Checklist/home-todo", "Morning Checklist/working-wednesday"},
 		},
 		{
-			name:          "Wednesday",
-			date:          time.Date(1970, time.January, 7, 12, 0, 0, 0, time.UTC),
-			expectedDay:   "Wednesday, 7 January 1970\n",
-			showTimesheet: false,
-			showWednesday: true,
-			showExpense:   false,
+			name:        "Last Wednesday of January 1970",
+			date:        time.Date(1970, time.January, 28, 12, 0, 0, 0, time.UTC),
+			items:       checklist.Default,
+			expectedDay: "Wednesday, 28 January 1970\n",
+			expected:    []string{"Morning Checklist/email", "Morning Checklist/calendar", "Morning Checklist/slack", "Morning Checklist/home-todo", "Morning Checklist/working-wednesday", "Morning Checklist/expenses"},
 		},
 		{
-			name:          "Last Wednesday of January 1970",
-			date:          time.Date(1970, time.January, 28, 12, 0, 0, 0, time.UTC),
-			expectedDay:   "Wednesday, 28 January 1970\n",
-			showTimesheet: false,
-			showWednesday: true,
-			showExpense:   true,
+			name:        "Last Friday of month",
+			date:        time.Date(1971, time.December, 31, 12, 0, 0, 0, time.UTC),
+			items:       checklist.Default,
+			expectedDay: "Friday, 31 December 1971\n",
+			expected:    []string{"Morning Checklist/email", "Morning Checklist/calendar", "Morning Checklist/slack", "Morning Checklist/home-todo", "Morning Checklist/timesheet", "Morning Checklist/expenses"},
 		},
 		{
-			name:          "Last Friday of month",
-			date:          time.Date(1971, time.December, 31, 12, 0, 0, 0, time.UTC),
-			expectedDay:   "Friday, 31 December 1971\n",
-			showTimesheet: true,
-			showWednesday: false,
-			showExpense:   true,
+			name:        "Unix Birthday",
+			date:        time.Date(1970, time.January, 1, 12, 0, 0, 0, time.UTC),
+			items:       checklist.Default,
+			expectedDay: "Thursday, 1 January 1970\n",
+			expected:    []string{"Morning Checklist/email", "Morning Checklist/calendar", "Morning Checklist/slack", "Morning Checklist/home-todo"},
 		},
 		{
-			name:          "Unix Birthday",
-			date:          time.Date(1970, time.January, 1, 12, 0, 0, 0, time.UTC),
-			expectedDay:   "Thursday, 1 January 1970\n",
-			showTimesheet: false,
-			showWednesday: false,
-			showExpense:   false,
+			name:        "Configured, first of the month",
+			date:        time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC),
+			items:       configured,
+			expectedDay: "Friday, 1 March 2024\n",
+			expected:    []string{"Morning Checklist/standup", "Admin/invoice"},
+		},
+		{
+			name:        "Configured, last Friday",
+			date:        time.Date(2024, time.March, 29, 12, 0, 0, 0, time.UTC),
+			items:       configured,
+			expectedDay: "Friday, 29 March 2024\n",
+			expected:    []string{"Morning Checklist/standup", "Admin/backup"},
+		},
+		{
+			name:        "Configured, last day on a weekend",
+			date:        time.Date(2024, time.March, 31, 12, 0, 0, 0, time.UTC),
+			items:       configured,
+			expectedDay: "Sunday, 31 March 2024\n",
+			expected:    []string{"Admin/invoice", "Home/plants"},
+		},
+		{
+			name:        "Nothing due",
+			date:        time.Date(2024, time.March, 31, 12, 0, 0, 0, time.UTC),
+			items:       configured[:1],
+			expectedDay: "Sunday, 31 March 2024\n",
+			expected:    nil,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			args := buildDayArgs(tc.date)
+			args := buildDayArgs(tc.date, tc.items)
 
 			if args.Day != tc.expectedDay {
 				t.Errorf("Expected Day to be %q, but got %q", tc.expectedDay, args.Day)
 			}
 
-			if args.ShowTimesheet != tc.showTimesheet {
-				t.Errorf("Expected ShowTimesheet to be %t, but got %t", tc.showTimesheet, args.ShowTimesheet)
+			var due []string
+			for _, section := range args.Checklist {
+				for _, item := range section.Items {
+					due = append(due, section.Name+"/"+item.ID)
+				}
 			}
-
-			if args.ShowWorkingWednesday != tc.showWednesday {
-				t.Errorf("Expected ShowWorkingWednesday to be %t, but got %t", tc.showWednesday, args.ShowWorkingWednesday)
-			}
-
-			if args.ShowExpenseTodo != tc.showExpense {
-				t.Errorf("Expected ShowExpenseTodo to be %t, but got %t", tc.showExpense, args.ShowExpenseTodo)
+			if !reflect.DeepEqual(due, tc.expected) {
+				t.Errorf("Expected the checklist to be %v, but got %v", tc.expected, due)
 			}
 		})
 	}
 }
 
+func TestChecklistItems(t *testing.T) {
+	items, err := checklistItems(&config.Config{})
+	if err != nil || len(items) != len(checklist.Default) {
+		t.Errorf("Expected the default checklist without config, but got %v, %v", items, err)
+	}
+
+	cfg := &config.Config{Checklist: []config.ChecklistItem{
+		{ID: "review", Label: "review PRs", Schedule: "mon, thu", Section: "Code"},
+	}}
+	items, err = checklistItems(cfg)
+	if err != nil {
+		t.Fatalf("checklistItems returned an error: %v", err)
+	}
+	if len(items) != 1 || items[0].Label != "review PRs" || items[0].Section != "Code" || !items[0].Schedule.Due(time.Date(2024, time.March, 7, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the configured item, but got %+v", items)
+	}
+
+	cfg.Checklist[0].Schedule = "fortnightly"
+	if _, err := checklistItems(cfg); err == nil {
+		t.Error("Expected an error for an unknown schedule")
+	}
+}
+
+func TestDayTemplateChecklist(t *testing.T) {
+	cfg := &config.Config{
+		VaultPath: t.TempDir(),
+		DayPath:   "days",
+		Checklist: []config.ChecklistItem{
+			{ID: "email", Label: "check email"},
+			{ID: "timesheet", Label: "time sheet", Schedule: "friday"},
+			{ID: "invoice", Label: "send invoice", Schedule: "day 1", Section: "Admin"},
+		},
+		Timesheet: config.TimesheetConfig{Format: "csv"},
+	}
+	args, err := linkedDayArgs(cfg, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("linkedDayArgs returned an error: %v", err)
+	}
+	var content strings.Builder
+	if err := newDayTemplate().Execute(&content, args); err != nil {
+		t.Fatalf("Executing the day template returned an error: %v", err)
+	}
+
+	expected := `# Friday, 1 March 2024
+
+
+## Morning Checklist
+
+- [ ] check email
+- [ ] time sheet ([[2024-W09 Timesheet.csv]])
+
+## Admin
+
+- [ ] send invoice
+
+## What do you want to accomplish today?
+
+- [ ]
+`
+	if content.String() != expected {
+		t.Errorf("Expected the day note to be\n%s\nbut got\n%s", expected, content.String())
+	}
+}
+
 // MockConfigReader allows us to control the config values during testing
 type MockConfigReader struct {
 	Config config.Config
@@ -149,10 +252,7 @@ func TestCreateDayFileQuarterly(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			testArgs := DayArgs{
-				Day:                  "Test Day\n",
-				ShowTimesheet:        false,
-				ShowWorkingWednesday: false,
-				ShowExpenseTodo:      false,
+				Day: "Test Day\n",
 			}
 
 			filePath, err := createDayFile(testArgs, tc.time)
@@ -201,10 +301,7 @@ func TestCreateDayFile(t *testing.T) {
 	// Test Time
 	testTime := time.Date(2024, time.January, 25, 12, 0, 0, 0, time.UTC)
 	testArgs := DayArgs{
-		Day:                  "Thursday, 25 January 2024\n",
-		ShowTimesheet:        false,
-		ShowWorkingWednesday: false,
-		ShowExpenseTodo:      false,
+		Day: "Thursday, 25 January 2024\n",
 	}
 
 	filePath, err := createDayFile(testArgs, testTime)
@@ -234,3 +331,108 @@ func TestCreateDayFile(t *testing.T) {
 	// Clean up temp dir
 	os.RemoveAll(tempDir)
 }
+
+func TestTodayAfterMidnight(t *testing.T) {
+	cfg := &config.Config{VaultPath: "vault", DayPath: "days", DayStartsAt: "04:00", Timezone: "UTC"}
+
+	// Still working at 1am on New Year's Day, so it's the last note of the year
+	now, err := today(cfg, clock.Fixed(time.Date(2025, time.January, 1, 1, 0, 0, 0, time.UTC)))
+	if err != nil {
+		t.Fatalf("today returned an error: %v", err)
+	}
+
+	filePath, err := dayFilePath(cfg, now)
+	if err != nil {
+		t.Fatalf("dayFilePath returned an error: %v", err)
+	}
+	expected := filepath.Join("vault", "days", "2024_Q4", "12-31-2024.md")
+	if filePath != expected {
+		t.Errorf("Expected file path to be %q, but got %q", expected, filePath)
+	}
+
+	args := buildDayArgs(now, checklist.Default)
+	if args.Day != "Tuesday, 31 December 2024\n" || checklist.Find(args.Checklist, "expenses") == nil {
+		t.Errorf("Expected the day args for 31 December 2024, but got %+v", args)
+	}
+}
+
+func TestSetDayNav(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		prev     string
+		next     string
+		expected string
+	}{
+		{
+			name:     "Blank line left for links",
+			content:  "# Friday\n\n\n## Morning Checklist\n",
+			next:     "3-4-2024",
+			expected: "# Friday\n\n[[3-4-2024]] →\n\n## Morning Checklist\n",
+		},
+		{
+			name:     "Keeps the other link",
+			content:  "# Monday\n\n← [[3-1-2024]] | [[3-8-2024]] →\n\n## Morning Checklist\n",
+			next:     "3-5-2024",
+			expected: "# Monday\n\n← [[3-1-2024]] | [[3-5-2024]] →\n\n## Morning Checklist\n",
+		},
+		{
+			name:     "Adds the other link",
+			content:  "# Tuesday\r\n\r\n[[3-6-2024]] →\r\n",
+			prev:     "3-4-2024",
+			expected: "# Tuesday\r\n\r\n← [[3-4-2024]] | [[3-6-2024]] →\r\n",
+		},
+		{
+			name:     "Written by hand",
+			content:  "# Wednesday\nBusy day\n",
+			prev:     "3-5-2024",
+			expected: "# Wednesday\n\n← [[3-5-2024]]\n\nBusy day\n",
+		},
+		{
+			name:     "No title",
+			content:  "Just notes\n",
+			prev:     "3-5-2024",
+			expected: "Just notes\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := setDayNav(tt.content, tt.prev, tt.next); got != tt.expected {
+				t.Errorf("Expected %q, but got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewDayNoteLinksNeighbours(t *testing.T) {
+	tempDir := t.TempDir()
+	mockConfig := MockConfigReader{Config: config.Config{VaultPath: tempDir, DayPath: "days"}}
+	config.ReadConfigMock = mockConfig.ReadConfig
+	defer func() { config.ReadConfigMock = nil }()
+	cfg := &mockConfig.Config
+
+	keep := func(content string) string { return content }
+	paths := map[int]string{}
+	for _, day := range []int{1, 5, 4} {
+		notePath, err := editDayNote(cfg, time.Date(2024, time.March, day, 0, 0, 0, 0, time.UTC), keep)
+		if err != nil {
+			t.Fatalf("editDayNote returned an error: %v", err)
+		}
+		paths[day] = notePath
+	}
+
+	expected := map[int]string{
+		1: "# Friday, 1 March 2024\n\n[[3-4-2024]] →\n\n## Morning Checklist",
+		4: "# Monday, 4 March 2024\n\n← [[3-1-2024]] | [[3-5-2024]] →\n\n## Morning Checklist",
+		5: "# Tuesday, 5 March 2024\n\n← [[3-4-2024]]\n\n## Morning Checklist",
+	}
+	for day, want := range expected {
+		content, err := os.ReadFile(paths[day])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(content), want) {
+			t.Errorf("Expected the note for the %dth to start with %q, but got %q", day, want, content)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"gnote/config"
+)
+
+// CommandEditor opens files with the editor command from the config, e.g. "code --wait"
+type CommandEditor struct {
+	Command string
+}
+
+func (c CommandEditor) OpenFile(filePath string) error {
+	fields := strings.Fields(c.Command)
+	editor := exec.Command(fields[0], append(fields[1:], filePath)...)
+	editor.Stdin = os.Stdin
+	editor.Stdout = os.Stdout
+	editor.Stderr = os.Stderr
+	if err := editor.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w", fields[0], err)
+	}
+	return nil
+}
+
+// newEditor returns the configured editor, or nvim when there isn't one.
+func newEditor(cfg *config.Config) Editor {
+	if strings.TrimSpace(cfg.Editor) == "" {
+		return NvimEditor{}
+	}
+	return CommandEditor{Command: cfg.Editor}
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gnote/clock"
+	"gnote/config"
+	"gnote/focus"
+
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// defaultFocus is how long a session runs when no duration is given, a pomodoro.
+const defaultFocus = 25 * time.Minute
+
+var focusCmd = &cobra.Command{
+	Use:   "focus [duration] [ticket]",
+	Short: "Run a focus session and record it in today's note",
+	Long: `Counts down a focus session, 25m unless another duration like 50m or 1h is given, optionally on a ticket.
+Press i when something interrupts you to count it, or s to stop early and say why.
+
+The session's start, end and interruptions go in the Focus section of today's note, with the day's total.`,
+	Args: cobra.MaximumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			return
+		}
+		planned, ticket, err := parseFocusArgs(args)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		final, err := tea.NewProgram(newFocusModel(planned, ticket, time.Now())).Run()
+		if err != nil {
+			fmt.Println("Error running the session:", err)
+			return
+		}
+		session := final.(focusModel).session
+
+		notePath, err := recordFocus(cfg, clock.Fixed(session.End), session)
+		if err != nil {
+			fmt.Println("Error recording the session:", err)
+			return
+		}
+		fmt.Printf("%s\nRecorded in %s\n", strings.TrimPrefix(session.String(), "- "), relativeTo(cfg.VaultPath, notePath))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(focusCmd)
+}
+
+// parseFocusArgs reads the optional duration and ticket, in that order.
+func parseFocusArgs(args []string) (time.Duration, string, error) {
+	planned := defaultFocus
+	if len(args) > 0 {
+		if d, err := time.ParseDuration(args[0]); err == nil {
+			if d <= 0 {
+				return 0, "", errors.New("a focus session needs a duration above zero")
+			}
+			planned = d
+			args = args[1:]
+		}
+	}
+	if len(args) > 1 {
+		return 0, "", fmt.Errorf("expected a duration then a ticket, but got %q", strings.Join(args, " "))
+	}
+	ticket := ""
+	if len(args) == 1 {
+		ticket = args[0]
+	}
+	return planned, ticket, nil
+}
+
+// recordFocus adds the session to the Focus section of the day note the session ended in.
+func recordFocus(cfg *config.Config, c clock.Clock, session focus.Session) (string, error) {
+	day, err := dayBoundary(cfg)
+	if err != nil {
+		return "", err
+	}
+	return editDayNote(cfg, day.Today(c), func(content string) string {
+		return upsertSection(content, focus.Heading, focus.AddSession(sectionBody(content, focus.Heading), session))
+	})
+}
+
+type focusTickMsg time.Time
+
+func focusTick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg { return focusTickMsg(t) })
+}
+
+// focusModel counts down the session, then, when it's stopped early, asks why.
+type focusModel struct {
+	session  focus.Session
+	now      time.Time
+	bar      progress.Model
+	reason   textinput.Model
+	asking   bool
+	finished bool
+}
+
+func newFocusModel(planned time.Duration, ticket string, start time.Time) focusModel {
+	reason := textinput.New()
+	reason.Prompt = "Why did you stop? "
+	reason.Placeholder = "a meeting, a colleague, done early"
+	return focusModel{
+		session: focus.Session{Ticket: ticket, Start: start, Planned: planned},
+		now:     start,
+		bar:     progress.New(progress.WithDefaultGradient(), progress.WithoutPercentage()),
+		reason:  reason,
+	}
+}
+
+func (m focusModel) Init() tea.Cmd {
+	return focusTick()
+}
+
+func (m focusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.asking {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "enter", "esc", "ctrl+c":
+				m.session.Reason = strings.TrimSpace(m.reason.Value())
+				m.finished = true
+				return m, tea.Quit
+			}
+		}
+		var cmd tea.Cmd
+		m.reason, cmd = m.reason.Update(msg)
+		return m, cmd
+	}
+
+	switch msg := msg.(type) {
+	case focusTickMsg:
+		m.now = time.Time(msg)
+		if !m.now.Before(m.session.Start.Add(m.session.Planned)) {
+			m.session.End = m.session.Start.Add(m.session.Planned)
+			m.finished = true
+			// Ring the terminal bell so the end of the session is noticed
+			return m, tea.Sequence(tea.Println("\a"), tea.Quit)
+		}
+		return m, focusTick()
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "i":
+			m.session.Interruptions++
+		case "s", "q", "esc", "ctrl+c":
+			m.session.End = m.now
+			m.session.Stopped = true
+			m.asking = true
+			m.reason.Focus()
+			return m, textinput.Blink
+		}
+	case tea.WindowSizeMsg:
+		m.bar.Width = min(msg.Width-4, 60)
+	}
+	return m, nil
+}
+
+var (
+	focusTitleStyle = lipgloss.NewStyle().Bold(true)
+	focusHelpStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+)
+
+func (m focusModel) View() string {
+	if m.finished {
+		return ""
+	}
+	if m.asking {
+		return fmt.Sprintf("Stopped after %s of %s.\n%s\n", focus.FormatDuration(m.session.End.Sub(m.session.Start)),
+			focus.FormatDuration(m.session.Planned), m.reason.View())
+	}
+
+	elapsed := m.now.Sub(m.session.Start)
+	left := (m.session.Planned - elapsed).Round(time.Second)
+	title := "Focus"
+	if m.session.Ticket != "" {
+		title += " on " + m.session.Ticket
+	}
+
+	var b strings.Builder
+	b.WriteString(focusTitleStyle.Render(title) + "\n\n")
+	fmt.Fprintf(&b, "%02d:%02d left\n", int(left.Minutes()), int(left.Seconds())%60)
+	b.WriteString(m.bar.ViewAs(float64(elapsed)/float64(m.session.Planned)) + "\n\n")
+	if m.session.Interruptions > 0 {
+		fmt.Fprintf(&b, "%d interruption(s)\n", m.session.Interruptions)
+	}
+	b.WriteString(focusHelpStyle.Render("i interrupted • s stop"))
+	return b.String()
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gnote/clock"
+	"gnote/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFocusSession(t *testing.T) {
+	start := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+	var m tea.Model = newFocusModel(25*time.Minute, "PROJ-1", start)
+
+	for _, msg := range []tea.Msg{
+		focusTickMsg(start.Add(5 * time.Minute)),
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")},
+		focusTickMsg(start.Add(12 * time.Minute)),
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")},
+		// Typing the reason doesn't count as interruptions
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("incident")},
+		tea.KeyMsg{Type: tea.KeyEnter},
+	} {
+		m, _ = m.Update(msg)
+	}
+	session := m.(focusModel).session
+	if !session.Stopped || session.Interruptions != 1 || session.Reason != "incident" || !session.End.Equal(start.Add(12*time.Minute)) {
+		t.Fatalf("Expected a session stopped after 12m with 1 interruption for an incident, but got %+v", session)
+	}
+
+	// A session that runs its course ends on time
+	var done tea.Model = newFocusModel(25*time.Minute, "", start)
+	done, _ = done.Update(focusTickMsg(start.Add(25 * time.Minute)))
+	if s := done.(focusModel).session; s.Stopped || !s.End.Equal(start.Add(25*time.Minute)) {
+		t.Errorf("Expected the session to end at 09:25, but got %+v", s)
+	}
+
+	tempDir := t.TempDir()
+	mockConfig := MockConfigReader{Config: config.Config{VaultPath: tempDir, DayPath: "days", Timezone: "UTC"}}
+	config.ReadConfigMock = mockConfig.ReadConfig
+	defer func() { config.ReadConfigMock = nil }()
+	for _, s := range []tea.Model{m, done} {
+		if _, err := recordFocus(&mockConfig.Config, clock.Fixed(s.(focusModel).session.End), s.(focusModel).session); err != nil {
+			t.Fatalf("recordFocus returned an error: %v", err)
+		}
+	}
+
+	note, err := os.ReadFile(filepath.Join(tempDir, "days", "2024_Q1", "3-4-2024.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "## Focus\n\n- 09:00–09:12 [[PROJ-1]] 12m of 25m, 1 interruption, stopped: incident\n" +
+		"- 09:00–09:25 25m, 0 interruptions\n\n**Total:** 37m in 2 sessions\n"
+	if !strings.HasSuffix(string(note), want) {
+		t.Errorf("Expected the note to end with %q, but got:\n%s", want, note)
+	}
+}
+
+func TestParseFocusArgs(t *testing.T) {
+	if planned, ticket, err := parseFocusArgs(nil); err != nil || planned != 25*time.Minute || ticket != "" {
+		t.Errorf("Expected 25m on nothing, but got %s, %q and %v", planned, ticket, err)
+	}
+	if planned, ticket, err := parseFocusArgs([]string{"PROJ-1"}); err != nil || planned != 25*time.Minute || ticket != "PROJ-1" {
+		t.Errorf("Expected 25m on PROJ-1, but got %s, %q and %v", planned, ticket, err)
+	}
+	if planned, ticket, err := parseFocusArgs([]string{"50m", "PROJ-1"}); err != nil || planned != 50*time.Minute || ticket != "PROJ-1" {
+		t.Errorf("Expected 50m on PROJ-1, but got %s, %q and %v", planned, ticket, err)
+	}
+	if _, _, err := parseFocusArgs([]string{"PROJ-1", "50m"}); err == nil {
+		t.Error("Expected an error with the ticket first")
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"strings"
+	"unicode"
+)
+
+// fuzzyScore reports whether every character of query appears in candidate in order, ignoring case,
+// and how well it matches. Matches at the start, at word boundaries and in consecutive runs score higher.
+func fuzzyScore(query string, candidate string) (int, bool) {
+	q := []rune(strings.ToLower(query))
+	c := []rune(strings.ToLower(candidate))
+	if len(q) == 0 {
+		return 0, true
+	}
+
+	score := 0
+	qi := 0
+	lastMatch := -1
+	for ci := 0; ci < len(c) && qi < len(q); ci++ {
+		if c[ci] != q[qi] {
+			continue
+		}
+		switch {
+		case ci == 0:
+			score += 10
+		case !unicode.IsLetter(c[ci-1]) && !unicode.IsDigit(c[ci-1]):
+			score += 8
+		}
+		if lastMatch == ci-1 {
+			score += 5
+		} else if lastMatch >= 0 {
+			score -= ci - lastMatch - 1
+		}
+		score++
+		lastMatch = ci
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+
+	if string(q) == string(c) {
+		score += 100
+	}
+	// Prefer shorter candidates when everything else is equal
+	score -= len(c) - len(q)
+	return score, true
+}
+
+// bestFuzzyScore is the best score of the query against any of the candidates.
+func bestFuzzyScore(query string, candidates []string) (int, bool) {
+	best, found := 0, false
+	for _, candidate := range candidates {
+		if score, ok := fuzzyScore(query, candidate); ok && (!found || score > best) {
+			best, found = score, true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	testCases := []struct {
+		query     string
+		candidate string
+		matches   bool
+	}{
+		{"proj1", "PROJ-1", true},
+		{"p12", "PROJ-12", true},
+		{"login", "PROJ-12 login form", true},
+		{"21", "PROJ-12", false},
+		{"", "anything", true},
+	}
+
+	for _, tc := range testCases {
+		if _, ok := fuzzyScore(tc.query, tc.candidate); ok != tc.matches {
+			t.Errorf("Expected %q matching %q to be %t", tc.query, tc.candidate, tc.matches)
+		}
+	}
+}
+
+func TestMatchProjectNotes(t *testing.T) {
+	projects := []projectNote{
+		{Name: "PROJ-120", Keys: []string{"PROJ-120"}},
+		{Name: "PROJ-12", Keys: []string{"PROJ-12", "login form"}, Archived: true},
+		{Name: "PROJ-1", Keys: []string{"PROJ-1"}},
+		{Name: "OTHER-5", Keys: []string{"OTHER-5"}},
+	}
+
+	testCases := []struct {
+		query    string
+		expected []string
+	}{
+		{"proj-1", []string{"PROJ-1", "PROJ-120", "PROJ-12"}},
+		{"login", []string{"PROJ-12"}},
+		{"other", []string{"OTHER-5"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			matches := matchProjectNotes(projects, tc.query)
+			var names []string
+			for _, match := range matches {
+				names = append(names, match.Name)
+			}
+			if len(names) != len(tc.expected) {
+				t.Fatalf("Expected %v, but got %v", tc.expected, names)
+			}
+			for i := range names {
+				if names[i] != tc.expected[i] {
+					t.Fatalf("Expected %v, but got %v", tc.expected, names)
+				}
+			}
+		})
+	}
+}
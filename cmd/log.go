@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gnote/clock"
+	"gnote/config"
+	"gnote/timesheet"
+
+	"github.com/spf13/cobra"
+)
+
+var logCmd = &cobra.Command{
+	Use:   "log <note>",
+	Short: "Log a note or time on a ticket in today's note",
+	Long: `Writes timestamped entries into the Log section of today's day note:
+
+  gnote log start PROJ-1      - 09:30 start [[PROJ-1]]
+  gnote log "found the bug"   - 10:15 found the bug
+  gnote log stop              - 11:00 stop [[PROJ-1]]
+
+The running timer is kept between runs, so it can be stopped from another shell or the next day.
+Starting a ticket stops the one running. 'gnote timesheet' adds up the time.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			return
+		}
+		entry := timesheet.Entry{Kind: timesheet.Note, Text: strings.Join(args, " ")}
+		if _, err := writeLog(cfg, clock.System, entry); err != nil {
+			fmt.Println("Error logging:", err)
+		}
+	},
+}
+
+var logStartCmd = &cobra.Command{
+	Use:   "start <ticket>",
+	Short: "Start the timer on a ticket",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			return
+		}
+		stopped, err := startTimer(cfg, clock.System, args[0])
+		if err != nil {
+			fmt.Println("Error starting the timer:", err)
+			return
+		}
+		if stopped.Ticket != "" {
+			fmt.Printf("Stopped %s after %s\n", stopped.Ticket, stopped.Duration().Round(time.Minute))
+		}
+		fmt.Printf("Started %s\n", args[0])
+	},
+}
+
+var logStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the running timer",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			return
+		}
+		stopped, err := stopTimer(cfg, clock.System)
+		if err != nil {
+			fmt.Println("Error stopping the timer:", err)
+			return
+		}
+		if stopped.Ticket == "" {
+			fmt.Println("No timer is running")
+			return
+		}
+		fmt.Printf("Stopped %s after %s\n", stopped.Ticket, stopped.Duration().Round(time.Minute))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logCmd)
+	logCmd.AddCommand(logStartCmd)
+	logCmd.AddCommand(logStopCmd)
+}
+
+// timerPath is where the running timer is kept, in the vault so it follows the notes.
+func timerPath(cfg *config.Config) string {
+	return filepath.Join(cfg.VaultPath, ".gnote", "timer.yaml")
+}
+
+// writeLog stamps the entry with the time on c and appends it to today's note.
+func writeLog(cfg *config.Config, c clock.Clock, entries ...timesheet.Entry) (string, error) {
+	day, err := dayBoundary(cfg)
+	if err != nil {
+		return "", err
+	}
+	date := day.Today(c)
+	at := c.Now().In(date.Location())
+
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		entry.Time = at
+		lines[i] = entry.String()
+	}
+	return appendToDayNote(cfg, date, timesheet.Heading, strings.Join(lines, "\n"))
+}
+
+// startTimer logs the start of work on ticket, stopping the running timer first, and returns the span
+// that was stopped, if any.
+func startTimer(cfg *config.Config, c clock.Clock, ticket string) (timesheet.Span, error) {
+	running, ok, err := timesheet.ReadTimer(timerPath(cfg))
+	if err != nil {
+		return timesheet.Span{}, err
+	}
+	if ok && strings.EqualFold(running.Ticket, ticket) {
+		return timesheet.Span{}, fmt.Errorf("%s has been running since %s", running.Ticket, running.Started.Format("15:04"))
+	}
+
+	var entries []timesheet.Entry
+	var stopped timesheet.Span
+	if ok {
+		entries = append(entries, timesheet.Entry{Kind: timesheet.Stop, Ticket: running.Ticket})
+		stopped = timesheet.Span{Ticket: running.Ticket, Start: running.Started, End: c.Now()}
+	}
+	entries = append(entries, timesheet.Entry{Kind: timesheet.Start, Ticket: ticket})
+	if _, err := writeLog(cfg, c, entries...); err != nil {
+		return timesheet.Span{}, err
+	}
+	return stopped, timesheet.WriteTimer(timerPath(cfg), timesheet.Timer{Ticket: ticket, Started: c.Now()})
+}
+
+// stopTimer logs the end of work on the running ticket and returns how long it ran, or an empty span
+// when no timer is running.
+func stopTimer(cfg *config.Config, c clock.Clock) (timesheet.Span, error) {
+	running, ok, err := timesheet.ReadTimer(timerPath(cfg))
+	if err != nil || !ok {
+		return timesheet.Span{}, err
+	}
+	if _, err := writeLog(cfg, c, timesheet.Entry{Kind: timesheet.Stop, Ticket: running.Ticket}); err != nil {
+		return timesheet.Span{}, err
+	}
+	return timesheet.Span{Ticket: running.Ticket, Start: running.Started, End: c.Now()}, timesheet.ClearTimer(timerPath(cfg))
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gnote/checklist"
+	"gnote/clock"
+	"gnote/config"
+	"gnote/timesheet"
+)
+
+func TestLogTimesheet(t *testing.T) {
+	tempDir := t.TempDir()
+	mockConfig := MockConfigReader{Config: config.Config{VaultPath: tempDir, DayPath: "days", Timezone: "UTC"}}
+	config.ReadConfigMock = mockConfig.ReadConfig
+	defer func() { config.ReadConfigMock = nil }()
+	cfg := &mockConfig.Config
+	at := func(day, hour, minute int) clock.Clock {
+		return clock.Fixed(time.Date(2024, time.March, day, hour, minute, 0, 0, time.UTC))
+	}
+
+	if _, err := startTimer(cfg, at(4, 9, 0), "PROJ-1"); err != nil {
+		t.Fatalf("startTimer returned an error: %v", err)
+	}
+	if _, err := writeLog(cfg, at(4, 9, 20), timesheet.Entry{Kind: timesheet.Note, Text: "found the bug"}); err != nil {
+		t.Fatalf("writeLog returned an error: %v", err)
+	}
+	stopped, err := startTimer(cfg, at(4, 10, 30), "PROJ-2")
+	if err != nil {
+		t.Fatalf("startTimer returned an error: %v", err)
+	}
+	if stopped.Ticket != "PROJ-1" || stopped.Duration() != 90*time.Minute {
+		t.Errorf("Expected starting PROJ-2 to stop PROJ-1 after 1h30m, but got %+v", stopped)
+	}
+	// Left running overnight and stopped the next morning
+	if _, err := stopTimer(cfg, at(5, 9, 0)); err != nil {
+		t.Fatalf("stopTimer returned an error: %v", err)
+	}
+	if stopped, err := stopTimer(cfg, at(5, 9, 5)); err != nil || stopped.Ticket != "" {
+		t.Errorf("Expected nothing to stop, but got %+v and %v", stopped, err)
+	}
+
+	note, err := os.ReadFile(filepath.Join(tempDir, "days", "2024_Q1", "3-4-2024.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "## Log\n\n- 09:00 start [[PROJ-1]]\n- 09:20 found the bug\n- 10:30 stop [[PROJ-1]]\n- 10:30 start [[PROJ-2]]\n"
+	if !strings.Contains(string(note), want) {
+		t.Errorf("Expected the note to contain %q, but got:\n%s", want, note)
+	}
+
+	sheet, err := readTimesheet(cfg, at(5, 12, 0), weekStart(time.Date(2024, time.March, 6, 0, 0, 0, 0, time.UTC)), 7)
+	if err != nil {
+		t.Fatalf("readTimesheet returned an error: %v", err)
+	}
+	var out bytes.Buffer
+	if err := timesheet.WriteCSV(&out, sheet.ByTicket("2006-01-02")); err != nil {
+		t.Fatal(err)
+	}
+	wantCSV := "ticket,2024-03-04,2024-03-05,2024-03-06,2024-03-07,2024-03-08,2024-03-09,2024-03-10,total\n" +
+		"PROJ-1,1.50,0.00,0.00,0.00,0.00,0.00,0.00,1.50\n" +
+		"PROJ-2,22.50,0.00,0.00,0.00,0.00,0.00,0.00,22.50\n" +
+		"Total,24.00,0.00,0.00,0.00,0.00,0.00,0.00,24.00\n"
+	if out.String() != wantCSV {
+		t.Errorf("Expected\n%s\nbut got\n%s", wantCSV, out.String())
+	}
+}
+
+func TestTimesheetExport(t *testing.T) {
+	tempDir := t.TempDir()
+	mockConfig := MockConfigReader{Config: config.Config{
+		VaultPath: tempDir,
+		DayPath:   "days",
+		Timezone:  "UTC",
+		Timesheet: config.TimesheetConfig{
+			Format:  "csv",
+			Columns: []config.TimesheetColumn{{Field: "ticket", Name: "Code"}, {Field: "hours", Name: "Hrs"}},
+		},
+	}}
+	config.ReadConfigMock = mockConfig.ReadConfig
+	defer func() { config.ReadConfigMock = nil }()
+	cfg := &mockConfig.Config
+
+	if _, err := startTimer(cfg, clock.Fixed(time.Date(2024, time.March, 8, 9, 0, 0, 0, time.UTC)), "PROJ-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stopTimer(cfg, clock.Fixed(time.Date(2024, time.March, 8, 9, 50, 0, 0, time.UTC))); err != nil {
+		t.Fatal(err)
+	}
+
+	friday := time.Date(2024, time.March, 8, 0, 0, 0, 0, time.UTC)
+	var out bytes.Buffer
+	err := exportTimesheet(&out, cfg, clock.Fixed(friday.Add(12*time.Hour)), weekStart(friday), weekStart(friday).AddDate(0, 0, 6), "csv", "", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("exportTimesheet returned an error: %v", err)
+	}
+	if want := "Code,Hrs\nPROJ-1,0.75\n"; out.String() != want {
+		t.Errorf("Expected %q, but got %q", want, out.String())
+	}
+
+	args, err := linkedDayArgs(cfg, friday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item := checklist.Find(args.Checklist, "timesheet"); item == nil || item.Link != "2024-W10 Timesheet.csv" {
+		t.Errorf("Expected Friday to link to the week's timesheet, but got %+v", item)
+	}
+}
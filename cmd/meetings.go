@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gnote/config"
+	"gnote/ics"
+)
+
+// Meeting is a calendar event on a day note's day.
+type Meeting struct {
+	Title  string
+	Start  time.Time
+	End    time.Time
+	AllDay bool
+}
+
+// Times is when the meeting is, like 09:30–09:45, or "All day:" for all-day events.
+func (m Meeting) Times() string {
+	if m.AllDay {
+		return "All day:"
+	}
+	return fmt.Sprintf("%s–%s", m.Start.Format("15:04"), m.End.Format("15:04"))
+}
+
+// dayMeetings reads the meetings on date's day note from the configured calendars, in the day note's
+// timezone. With day_starts_at, meetings after midnight but before the day starts are on the day before.
+// Calendars and events that can't be read are left out, and the problems returned, so that a bad file in a
+// synced folder doesn't stop the day note being made.
+func dayMeetings(cfg *config.Config, date time.Time) ([]Meeting, []error) {
+	if len(cfg.Calendars) == 0 {
+		return nil, nil
+	}
+	day, err := dayBoundary(cfg)
+	if err != nil {
+		return nil, []error{err}
+	}
+	loc := date.Location()
+
+	var (
+		events   []ics.Event
+		problems []error
+	)
+	for _, calendar := range cfg.Calendars {
+		calendarEvents, calendarProblems := readCalendar(expandHome(calendar), loc)
+		events = append(events, calendarEvents...)
+		problems = append(problems, calendarProblems...)
+	}
+
+	var meetings []Meeting
+	for _, e := range ics.Occurrences(events, day.Start(date), day.End(date)) {
+		if e.AllDay {
+			// All-day events are on calendar dates, whenever the day note's day starts
+			if date.Before(e.Start) || !date.Before(e.End) {
+				continue
+			}
+			meetings = append(meetings, Meeting{Title: e.Summary, Start: e.Start, End: e.End, AllDay: true})
+			continue
+		}
+		meetings = append(meetings, Meeting{Title: e.Summary, Start: e.Start.In(loc), End: e.End.In(loc)})
+	}
+	return meetings, problems
+}
+
+// readCalendar reads the events in an ICS file, or every ICS file under a folder, as CalDAV syncing
+// tools like vdirsyncer leave them. It reads what it can, returning the problems with the rest.
+func readCalendar(path string, loc *time.Location) ([]ics.Event, []error) {
+	var (
+		paths    []string
+		problems []error
+	)
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			problems = append(problems, fmt.Errorf("reading calendar %s: %w", p, err))
+			return nil
+		}
+		if !d.IsDir() && (p == path || strings.EqualFold(filepath.Ext(p), ".ics")) {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		problems = append(problems, fmt.Errorf("reading calendar %s: %w", path, err))
+	}
+
+	var events []ics.Event
+	for _, p := range paths {
+		file, err := os.Open(p)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("reading calendar %s: %w", p, err))
+			continue
+		}
+		fileEvents, skipped, err := ics.ParseEvents(file, loc)
+		file.Close()
+		if err != nil {
+			problems = append(problems, fmt.Errorf("reading calendar %s: %w", p, err))
+			continue
+		}
+		for _, skip := range skipped {
+			problems = append(problems, fmt.Errorf("skipping an event in %s: %w", p, skip))
+		}
+		events = append(events, fileEvents...)
+	}
+	return events, problems
+}
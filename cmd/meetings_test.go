@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gnote/checklist"
+	"gnote/clock"
+	"gnote/config"
+)
+
+func TestDayMeetings(t *testing.T) {
+	cfg := &config.Config{
+		VaultPath:   t.TempDir(),
+		DayPath:     "days",
+		DayStartsAt: "04:00",
+		Timezone:    "Europe/London",
+		Calendars:   []string{"testdata/calendar/work.ics", "testdata/calendar/personal"},
+	}
+
+	// Early on Tuesday the 12th is still Monday the 11th's note
+	london, _ := time.LoadLocation("Europe/London")
+	now, err := today(cfg, clock.Fixed(time.Date(2024, time.March, 12, 2, 0, 0, 0, london)))
+	if err != nil {
+		t.Fatalf("today returned an error: %v", err)
+	}
+
+	meetings, problems := dayMeetings(cfg, now)
+	if len(problems) > 0 {
+		t.Fatalf("dayMeetings had problems: %v", problems)
+	}
+	args := buildDayArgs(now, checklist.Default)
+	args.Meetings = meetings
+	var content strings.Builder
+	if err := newDayTemplate().Execute(&content, args); err != nil {
+		t.Fatalf("Executing the day template returned an error: %v", err)
+	}
+
+	// The cancelled meeting, the deleted standup and Tuesday's events are left out; the handover
+	// after midnight is before the day starts, and the New York call is in London time
+	expected := `## Meetings
+
+- All day: Team offsite
+- 10:00–10:15 Standup (moved)
+- 15:00–15:30 Call with the New York office
+- 16:00–17:00 Retro
+- 01:30–01:45 On-call handover
+
+## What do you want to accomplish today?`
+	if !strings.Contains(content.String(), expected) {
+		t.Errorf("Expected the day note to contain\n%s\nbut got\n%s", expected, content.String())
+	}
+}
+
+func TestDayWithoutMeetings(t *testing.T) {
+	cfg := &config.Config{VaultPath: t.TempDir(), DayPath: "days", Calendars: []string{"testdata/calendar"}}
+
+	// The standup was deleted on the 4th
+	meetings, problems := dayMeetings(cfg, time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC))
+	if len(problems) > 0 {
+		t.Fatalf("dayMeetings had problems: %v", problems)
+	}
+	if len(meetings) != 0 {
+		t.Errorf("Expected no meetings, but got %+v", meetings)
+	}
+
+	var content strings.Builder
+	if err := newDayTemplate().Execute(&content, buildDayArgs(time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC), checklist.Default)); err != nil {
+		t.Fatalf("Executing the day template returned an error: %v", err)
+	}
+	if strings.Contains(content.String(), "## Meetings") {
+		t.Errorf("Expected no Meetings section, but got\n%s", content.String())
+	}
+}
+
+func TestDayNoteWithBadCalendars(t *testing.T) {
+	root := t.TempDir()
+	synced := filepath.Join(root, "synced")
+	if err := os.MkdirAll(synced, 0755); err != nil {
+		t.Fatal(err)
+	}
+	calendar := func(events string) string {
+		return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + events + "END:VCALENDAR\r\n"
+	}
+	files := map[string]string{
+		"good.ics": calendar("BEGIN:VEVENT\r\nSUMMARY:Standup\r\nDTSTART:20240311T093000Z\r\nDTEND:20240311T094500Z\r\nEND:VEVENT\r\n" +
+			"BEGIN:VEVENT\r\nSUMMARY:Ping\r\nRRULE:FREQ=HOURLY\r\nDTSTART:20240311T100000Z\r\nEND:VEVENT\r\n"),
+		"broken.ics": calendar("BEGIN:VEVENT\r\nSUMMARY:Lunch\r\nDTSTART:tomorrow\r\nEND:VEVENT\r\n"),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(synced, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mockConfig := MockConfigReader{Config: config.Config{
+		VaultPath: root,
+		DayPath:   "days",
+		Calendars: []string{synced, filepath.Join(root, "not-synced-yet")},
+	}}
+	config.ReadConfigMock = mockConfig.ReadConfig
+	defer func() { config.ReadConfigMock = nil }()
+
+	monday := time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC)
+	meetings, problems := dayMeetings(&mockConfig.Config, monday)
+	if len(meetings) != 1 || meetings[0].Title != "Standup" {
+		t.Errorf("Expected only the standup, but got %+v", meetings)
+	}
+	// The hourly event, the unreadable start and the missing folder
+	if len(problems) != 3 {
+		t.Errorf("Expected 3 problems, but got %v", problems)
+	}
+
+	// The note is still made, with the meetings that could be read
+	notePath, err := createDayFile(DayArgs{Day: "Monday, 11 March 2024\n"}, monday)
+	if err != nil {
+		t.Fatalf("createDayFile returned an error: %v", err)
+	}
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "## Meetings\n\n- 09:30–09:45 Standup\n") {
+		t.Errorf("Expected the standup in the day note, but got\n%s", content)
+	}
+}
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gnote/config"
+	"gnote/frontmatter"
+
+	"github.com/spf13/cobra"
+)
+
+var openFile string
+
+// openCmd represents the open command
+var openCmd = &cobra.Command{
+	Use:   "open [query]",
+	Short: "Open a ticket's notes in your editor",
+	Long: `Fuzzy matches the query against project folder names and the id and aliases in their description
+front matter, searching projects first and then archives, and opens the chosen project's note.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			return
+		}
+
+		var query string
+		if len(args) == 1 {
+			query = args[0]
+		}
+
+		projects, err := listProjectNotes(cfg)
+		if err != nil {
+			fmt.Println("Error listing projects:", err)
+			return
+		}
+		matches := matchProjectNotes(projects, query)
+		if len(matches) == 0 {
+			fmt.Printf("No projects match '%s'\n", query)
+			return
+		}
+
+		selected := matches[0].Path
+		if len(matches) > 1 && !matches[0].hasKey(query) {
+			items := make([]PickerItem, len(projects))
+			for i, project := range projects {
+				items[i] = PickerItem{
+					Label:   project.label(),
+					Keys:    project.Keys,
+					Preview: projectFilePath(project.Path, openFile),
+					Value:   project.Path,
+				}
+				if project.Archived {
+					items[i].Group = 1
+				}
+			}
+			selected, err = Picker{
+				Title:  "Select project to open:",
+				Items:  items,
+				Filter: query,
+			}.PickOne()
+			if err != nil {
+				fmt.Println("Error during selection:", err)
+				return
+			}
+		}
+
+		filePath := projectFilePath(selected, openFile)
+		if _, err := os.Stat(filePath); err != nil {
+			fmt.Printf("Error opening '%s': %v\n", filepath.Base(selected), err)
+			return
+		}
+		editor := newEditor(cfg)
+		if err := editor.OpenFile(filePath); err != nil {
+			fmt.Printf("Failed to open file in editor: %s\n", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(openCmd)
+	openCmd.Flags().StringVarP(&openFile, "file", "f", "description", "Which note to open: description, todo, investigation, estimate or a file name")
+}
+
+// projectNote is a project folder, either current or archived, and the names it can be found by.
+type projectNote struct {
+	Name     string
+	Path     string
+	Archived bool
+	Keys     []string
+}
+
+func (p projectNote) label() string {
+	if p.Archived {
+		return fmt.Sprintf("%s (archived %s)", p.Name, filepath.Base(filepath.Dir(p.Path)))
+	}
+	return p.Name
+}
+
+// hasKey reports whether the project goes by exactly this name, ignoring case.
+func (p projectNote) hasKey(name string) bool {
+	for _, key := range p.Keys {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// listProjectNotes lists the current projects followed by the archived ones.
+func listProjectNotes(cfg *config.Config) ([]projectNote, error) {
+	archivesPath := filepath.Join(cfg.VaultPath, cfg.ArchivesPath)
+
+	paths, err := allProjectPaths(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []projectNote
+	for _, path := range paths {
+		keys, err := projectNoteKeys(path)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, projectNote{
+			Name:     filepath.Base(path),
+			Path:     path,
+			Archived: strings.HasPrefix(path, archivesPath+string(filepath.Separator)),
+			Keys:     append([]string{filepath.Base(path)}, keys...),
+		})
+	}
+	return projects, nil
+}
+
+// projectNoteKeys reads the id and aliases from the project's description note.
+func projectNoteKeys(projectPath string) ([]string, error) {
+	doc, err := frontmatter.ReadFile(projectNotePath(projectPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var meta struct {
+		ID      string `yaml:"id"`
+		Aliases any    `yaml:"aliases"`
+	}
+	if err := doc.Decode(&meta); err != nil {
+		// A note with broken front matter can still be found by its folder name
+		return nil, nil
+	}
+
+	var keys []string
+	if meta.ID != "" {
+		keys = append(keys, meta.ID)
+	}
+	switch aliases := meta.Aliases.(type) {
+	case string:
+		keys = append(keys, aliases)
+	case []any:
+		for _, alias := range aliases {
+			if s, ok := alias.(string); ok {
+				keys = append(keys, s)
+			}
+		}
+	}
+	return keys, nil
+}
+
+// matchProjectNotes returns the projects matching the query, current projects before archived ones
+// and the best matches first.
+func matchProjectNotes(projects []projectNote, query string) []projectNote {
+	type scored struct {
+		project projectNote
+		score   int
+	}
+	var matches []scored
+	for _, project := range projects {
+		if score, ok := bestFuzzyScore(query, project.Keys); ok {
+			matches = append(matches, scored{project, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].project.Archived != matches[j].project.Archived {
+			return !matches[i].project.Archived
+		}
+		return matches[i].score > matches[j].score
+	})
+
+	result := make([]projectNote, len(matches))
+	for i, match := range matches {
+		result[i] = match.project
+	}
+	return result
+}
+
+// projectFilePath finds one of the project's notes by the name of the file generator that wrote it.
+func projectFilePath(projectPath string, file string) string {
+	switch strings.ToLower(file) {
+	case "", "description", "desc":
+		return projectNotePath(projectPath)
+	case "todo":
+		return filepath.Join(projectPath, "TODO.md")
+	case "investigation":
+		return filepath.Join(projectPath, "Investigation.md")
+	case "estimate":
+		return filepath.Join(projectPath, estimateFileName)
+	}
+	if filepath.Ext(file) == "" {
+		file += ".md"
+	}
+	return filepath.Join(projectPath, file)
+}
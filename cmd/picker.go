@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var ErrPickerCancelled = errors.New("selection cancelled")
+
+type PickerItem struct {
+	Label string
+	// Keys are matched against the filter as well as the label, e.g. a ticket's id and aliases
+	Keys []string
+	// Preview is the path of a note whose first lines are shown under the list
+	Preview string
+	// Group keeps items in order across scores: every match in group 0 comes before any in group 1
+	Group int
+	Value string
+}
+
+// Picker is a fuzzy-filtering list for choosing notes, used by every command that picks one.
+type Picker struct {
+	Title string
+	Items []PickerItem
+	// Multi allows choosing several items with tab
+	Multi bool
+	// Filter is the text the filter starts with
+	Filter       string
+	PreviewLines int
+	Height       int
+}
+
+// Run shows the picker and returns the chosen items, or ErrPickerCancelled.
+func (p Picker) Run() ([]PickerItem, error) {
+	if len(p.Items) == 0 {
+		return nil, errors.New("nothing to pick from")
+	}
+	final, err := tea.NewProgram(newPickerModel(p)).Run()
+	if err != nil {
+		return nil, err
+	}
+	m := final.(pickerModel)
+	if m.cancelled {
+		return nil, ErrPickerCancelled
+	}
+	return m.chosen(), nil
+}
+
+// PickOne runs a single-choice picker and returns the chosen item's value.
+func (p Picker) PickOne() (string, error) {
+	p.Multi = false
+	items, err := p.Run()
+	if err != nil {
+		return "", err
+	}
+	return items[0].Value, nil
+}
+
+type pickerModel struct {
+	picker    Picker
+	input     textinput.Model
+	visible   []int
+	cursor    int
+	offset    int
+	selected  map[int]bool
+	previews  map[string]string
+	done      bool
+	cancelled bool
+}
+
+func newPickerModel(p Picker) pickerModel {
+	if p.PreviewLines == 0 {
+		p.PreviewLines = 8
+	}
+	if p.Height == 0 {
+		p.Height = 10
+	}
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "type to filter"
+	input.SetValue(p.Filter)
+	input.Focus()
+
+	m := pickerModel{
+		picker:   p,
+		input:    input,
+		selected: map[int]bool{},
+		previews: map[string]string{},
+	}
+	m.visible = filterPickerItems(p.Items, p.Filter)
+	return m
+}
+
+// filterPickerItems returns the indexes of the items matching the query, best match first within each group.
+func filterPickerItems(items []PickerItem, query string) []int {
+	type scored struct {
+		index int
+		score int
+	}
+	var matches []scored
+	for i, item := range items {
+		if score, ok := bestFuzzyScore(query, append([]string{item.Label}, item.Keys...)); ok {
+			matches = append(matches, scored{i, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := items[matches[i].index], items[matches[j].index]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		return matches[i].score > matches[j].score
+	})
+
+	visible := make([]int, len(matches))
+	for i, match := range matches {
+		visible[i] = match.index
+	}
+	return visible
+}
+
+func (m pickerModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m pickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			m.cancelled = true
+			return m, tea.Quit
+		case "enter":
+			if len(m.chosen()) == 0 {
+				return m, nil
+			}
+			m.done = true
+			return m, tea.Quit
+		case "up", "ctrl+p", "ctrl+k":
+			m.moveCursor(-1)
+			return m, nil
+		case "down", "ctrl+n", "ctrl+j":
+			m.moveCursor(1)
+			return m, nil
+		case "pgup":
+			m.moveCursor(-m.picker.Height)
+			return m, nil
+		case "pgdown":
+			m.moveCursor(m.picker.Height)
+			return m, nil
+		case "tab":
+			if m.picker.Multi && len(m.visible) > 0 {
+				index := m.visible[m.cursor]
+				m.selected[index] = !m.selected[index]
+				m.moveCursor(1)
+			}
+			return m, nil
+		case "ctrl+a":
+			if m.picker.Multi {
+				// Toggle every visible item: select them all unless they all already are
+				all := true
+				for _, index := range m.visible {
+					all = all && m.selected[index]
+				}
+				for _, index := range m.visible {
+					m.selected[index] = !all
+				}
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	previous := m.input.Value()
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != previous {
+		m.visible = filterPickerItems(m.picker.Items, m.input.Value())
+		m.cursor, m.offset = 0, 0
+	}
+	return m, cmd
+}
+
+func (m *pickerModel) moveCursor(delta int) {
+	if len(m.visible) == 0 {
+		return
+	}
+	m.cursor = max(0, min(len(m.visible)-1, m.cursor+delta))
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.picker.Height {
+		m.offset = m.cursor - m.picker.Height + 1
+	}
+}
+
+// chosen is what enter picks: the selected items, or the one under the cursor when none are selected.
+func (m pickerModel) chosen() []PickerItem {
+	var items []PickerItem
+	for i, item := range m.picker.Items {
+		if m.selected[i] {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 && len(m.visible) > 0 {
+		items = append(items, m.picker.Items[m.visible[m.cursor]])
+	}
+	return items
+}
+
+var (
+	pickerTitleStyle   = lipgloss.NewStyle().Bold(true)
+	pickerCursorStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Bold(true)
+	pickerPreviewStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("245")).
+				BorderStyle(lipgloss.NormalBorder()).BorderLeft(true).PaddingLeft(1)
+	pickerHelpStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+)
+
+func (m pickerModel) View() string {
+	if m.done || m.cancelled {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(pickerTitleStyle.Render(m.picker.Title) + "\n")
+	b.WriteString(m.input.View() + "\n")
+
+	end := min(len(m.visible), m.offset+m.picker.Height)
+	for i := m.offset; i < end; i++ {
+		index := m.visible[i]
+		cursor, mark := "  ", ""
+		if m.picker.Multi {
+			mark = "[ ] "
+			if m.selected[index] {
+				mark = "[x] "
+			}
+		}
+		line := mark + m.picker.Items[index].Label
+		if i == m.cursor {
+			cursor = "> "
+			line = pickerCursorStyle.Render(line)
+		}
+		b.WriteString(cursor + line + "\n")
+	}
+	b.WriteString(pickerHelpStyle.Render(fmt.Sprintf("  %d/%d", len(m.visible), len(m.picker.Items))) + "\n")
+
+	if len(m.visible) > 0 {
+		if preview := m.preview(m.picker.Items[m.visible[m.cursor]]); preview != "" {
+			b.WriteString(pickerPreviewStyle.Render(preview) + "\n")
+		}
+	}
+
+	help := "↑/↓ move • enter choose • esc cancel"
+	if m.picker.Multi {
+		help = "↑/↓ move • tab select • ctrl+a select all • enter choose • esc cancel"
+	}
+	b.WriteString(pickerHelpStyle.Render(help))
+	return b.String()
+}
+
+// preview reads the first lines of the item's note, remembering them so scrolling doesn't re-read files.
+func (m pickerModel) preview(item PickerItem) string {
+	if item.Preview == "" {
+		return ""
+	}
+	if preview, ok := m.previews[item.Preview]; ok {
+		return preview
+	}
+	preview := readFirstLines(item.Preview, m.picker.PreviewLines)
+	m.previews[item.Preview] = preview
+	return preview
+}
+
+func readFirstLines(path string, n int) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for len(lines) < n && scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func pickerItems(labels ...string) []PickerItem {
+	items := make([]PickerItem, len(labels))
+	for i, label := range labels {
+		items[i] = PickerItem{Label: label, Value: label}
+	}
+	return items
+}
+
+func pickerLabels(items []PickerItem, indexes []int) []string {
+	labels := make([]string, len(indexes))
+	for i, index := range indexes {
+		labels[i] = items[index].Label
+	}
+	return labels
+}
+
+func TestFilterPickerItems(t *testing.T) {
+	items := pickerItems("PROJ-120", "PROJ-1", "OTHER-5", "PROJ-12")
+	items[0].Group = 1
+
+	testCases := []struct {
+		query    string
+		expected []string
+	}{
+		{"", []string{"PROJ-1", "OTHER-5", "PROJ-12", "PROJ-120"}},
+		{"proj-1", []string{"PROJ-1", "PROJ-12", "PROJ-120"}},
+		{"oth", []string{"OTHER-5"}},
+		{"zzz", []string{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			got := pickerLabels(items, filterPickerItems(items, tc.query))
+			if len(got) != len(tc.expected) {
+				t.Fatalf("Expected %v, but got %v", tc.expected, got)
+			}
+			for i := range got {
+				if got[i] != tc.expected[i] {
+					t.Fatalf("Expected %v, but got %v", tc.expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPickerModelMultiSelect(t *testing.T) {
+	var m tea.Model = newPickerModel(Picker{Items: pickerItems("a-1", "b-2", "c-3"), Multi: true})
+
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyTab},  // select a-1
+		{Type: tea.KeyDown}, // skip b-2
+		{Type: tea.KeyTab},  // select c-3
+	} {
+		m, _ = m.Update(key)
+	}
+
+	chosen := m.(pickerModel).chosen()
+	if len(chosen) != 2 || chosen[0].Value != "a-1" || chosen[1].Value != "c-3" {
+		t.Errorf("Expected a-1 and c-3 to be chosen, but got %+v", chosen)
+	}
+
+	// Typing filters the list and enter without a selection picks the item under the cursor
+	m = newPickerModel(Picker{Items: pickerItems("a-1", "b-2", "c-3")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	chosen = m.(pickerModel).chosen()
+	if len(chosen) != 1 || chosen[0].Value != "b-2" {
+		t.Errorf("Expected b-2 to be chosen, but got %+v", chosen)
+	}
+}
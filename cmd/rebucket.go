@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gnote/config"
+	"gnote/daynote"
+	"gnote/frontmatter"
+	"gnote/period"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	rebucketDryRun          bool
+	rebucketFrom            string
+	rebucketFromFiscalStart int
+)
+
+// rebucketCmd represents the rebucket command
+var rebucketCmd = &cobra.Command{
+	Use:   "rebucket",
+	Short: "Move day notes and archives into the configured period folders",
+	Long: `After changing period_folders or fiscal_year_start_month, moves day notes and archived projects
+from the folders they were filed in (calendar quarters unless --from and --from-fiscal-year-start say otherwise)
+into the new ones. Archived projects go by their completed date, or the start of their old folder's period,
+which is then recorded as their completed date so a later run leaves them where they are. Nothing is overwritten.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			return
+		}
+		calendar, bucket, err := periodFolders(cfg)
+		if err != nil {
+			fmt.Println("Error reading period settings:", err)
+			return
+		}
+		fromCalendar, err := period.NewCalendar(rebucketFromFiscalStart)
+		if err != nil {
+			fmt.Println("Error in --from-fiscal-year-start:", err)
+			return
+		}
+		fromBucket, err := period.ParseBucket(rebucketFrom)
+		if err != nil {
+			fmt.Println("Error in --from:", err)
+			return
+		}
+
+		verb := "Moved"
+		if rebucketDryRun {
+			verb = "Would move"
+		}
+
+		if cfg.DayFolderFormat != "" {
+			fmt.Println("day_folder_format is set, so day notes aren't filed by period and stay where they are")
+		} else if err := rebucketDayNotes(cfg, fromCalendar, fromBucket, verb); err != nil {
+			fmt.Println("Error moving day notes:", err)
+			return
+		}
+
+		archivePath := filepath.Join(cfg.VaultPath, cfg.ArchivesPath)
+		moves, err := planArchiveRebucket(archivePath, fromCalendar, fromBucket, calendar, bucket)
+		if err != nil {
+			fmt.Println("Error planning archive moves:", err)
+			return
+		}
+		for _, move := range moves {
+			fmt.Printf("%s %s to %s\n", verb, relativeTo(archivePath, move.From), relativeTo(archivePath, move.To))
+			if rebucketDryRun {
+				continue
+			}
+			if err := moveArchivedProject(move); err != nil {
+				fmt.Printf("Error moving '%s': %v\n", move.From, err)
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rebucketCmd)
+	rebucketCmd.Flags().BoolVar(&rebucketDryRun, "dry-run", false, "Only list what would be moved")
+	rebucketCmd.Flags().StringVar(&rebucketFrom, "from", "quarter", "How notes are filed now: quarter, month, week or half")
+	rebucketCmd.Flags().IntVar(&rebucketFromFiscalStart, "from-fiscal-year-start", 1, "The month the current folders' fiscal year starts in")
+}
+
+func rebucketDayNotes(cfg *config.Config, fromCalendar period.Calendar, fromBucket period.Bucket, verb string) error {
+	from, err := daynote.NewLayout(fromBucket.Format(), cfg.DayFilenameFormat, fromCalendar)
+	if err != nil {
+		return err
+	}
+	to, err := dayLayout(cfg)
+	if err != nil {
+		return err
+	}
+
+	root := filepath.Join(cfg.VaultPath, cfg.DayPath)
+	moves, err := daynote.PlanMigration(root, from, to, time.Local)
+	if err != nil {
+		return err
+	}
+	for _, move := range moves {
+		fmt.Printf("%s %s to %s\n", verb, relativeTo(root, move.From), relativeTo(root, move.To))
+	}
+	if rebucketDryRun {
+		return nil
+	}
+	return daynote.ApplyMigration(root, moves)
+}
+
+// planArchiveRebucket works out which period folder each archived project belongs in now.
+// Projects already in the right folder are left out, and projects whose date can't be worked out are reported and skipped.
+func planArchiveRebucket(archivePath string, fromCalendar period.Calendar, fromBucket period.Bucket, calendar period.Calendar, bucket period.Bucket) ([]daynote.Move, error) {
+	projects, err := listArchivedProjects(archivePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var moves []daynote.Move
+	targets := map[string]bool{}
+	for _, project := range projects {
+		date, ok := projectCompleted(project)
+		if !ok {
+			date, err = fromCalendar.Start(fromBucket, filepath.Base(filepath.Dir(project)), time.Local)
+			if err != nil {
+				fmt.Printf("Skipping '%s': no completed date and %v\n", project, err)
+				continue
+			}
+		}
+
+		target := filepath.Join(archivePath, calendar.Folder(bucket, date), filepath.Base(project))
+		if target == project {
+			continue
+		}
+		if _, err := os.Stat(target); err == nil || targets[target] {
+			return nil, fmt.Errorf("can't move %s to %s, it already exists", project, target)
+		}
+		targets[target] = true
+		moves = append(moves, daynote.Move{Date: date, From: project, To: target})
+	}
+	return moves, nil
+}
+
+func moveArchivedProject(move daynote.Move) error {
+	if err := os.MkdirAll(filepath.Dir(move.To), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(move.From, move.To); err != nil {
+		return err
+	}
+	// Clear away the old period folder once its last project has moved out
+	os.Remove(filepath.Dir(move.From))
+	// A project filed by its old folder keeps that date, since its new folder may not read back
+	// as the same period under the --from settings
+	return markCompleted(move.To, move.Date)
+}
+
+// projectCompleted reads the completed date from a project's description note.
+func projectCompleted(projectPath string) (time.Time, bool) {
+	doc, err := frontmatter.ReadFile(projectNotePath(projectPath))
+	if err != nil {
+		return time.Time{}, false
+	}
+	value, ok := doc.Get("completed")
+	if !ok {
+		return time.Time{}, false
+	}
+	completed, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	return completed, err == nil
+}
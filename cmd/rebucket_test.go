@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gnote/config"
+	"gnote/period"
+)
+
+// writeArchive lays out archived projects, each with its description note, under archivePath.
+// A project with a completed date gets it in its frontmatter.
+func writeArchive(t *testing.T, archivePath string, completed map[string]string) {
+	t.Helper()
+	for project, date := range completed {
+		dir := filepath.Join(archivePath, project)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		content := "# " + filepath.Base(project) + "\n"
+		if date != "" {
+			content = "---\ncompleted: " + date + "\n---\n" + content
+		}
+		if err := os.WriteFile(projectNotePath(dir), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestPlanArchiveRebucket(t *testing.T) {
+	quarters, _ := period.NewCalendar(1)
+	fiscal, _ := period.NewCalendar(4)
+
+	testCases := []struct {
+		name     string
+		calendar period.Calendar
+		bucket   period.Bucket
+		expected map[string]string
+	}{
+		{
+			name:     "fiscal quarters",
+			calendar: fiscal,
+			bucket:   period.Quarterly,
+			expected: map[string]string{
+				"2024_Q1/PROJ-1": "2024_Q4/PROJ-1",
+				"2024_Q3/PROJ-2": "2025_Q2/PROJ-2",
+			},
+		},
+		{
+			name:     "months",
+			calendar: quarters,
+			bucket:   period.Monthly,
+			expected: map[string]string{
+				"2024_Q1/PROJ-1": "2024-02/PROJ-1",
+				"2024_Q3/PROJ-2": "2024-07/PROJ-2",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			archivePath := t.TempDir()
+			// PROJ-1 goes by its completed date, PROJ-2 by the start of its old quarter
+			writeArchive(t, archivePath, map[string]string{
+				"2024_Q1/PROJ-1": "2024-02-10",
+				"2024_Q3/PROJ-2": "",
+			})
+
+			moves, err := planArchiveRebucket(archivePath, quarters, period.Quarterly, tc.calendar, tc.bucket)
+			if err != nil {
+				t.Fatalf("planArchiveRebucket returned an error: %v", err)
+			}
+			if len(moves) != len(tc.expected) {
+				t.Fatalf("Expected %d moves, got %v", len(tc.expected), moves)
+			}
+			for _, move := range moves {
+				from := relativeTo(archivePath, move.From)
+				if to := relativeTo(archivePath, move.To); to != tc.expected[from] {
+					t.Errorf("Expected %s to move to %s, got %s", from, tc.expected[from], to)
+				}
+				if err := moveArchivedProject(move); err != nil {
+					t.Fatalf("moveArchivedProject returned an error: %v", err)
+				}
+			}
+			for _, to := range tc.expected {
+				if _, err := os.Stat(projectNotePath(filepath.Join(archivePath, to))); err != nil {
+					t.Errorf("Expected %s to have moved with its note: %v", to, err)
+				}
+			}
+			if _, err := os.Stat(filepath.Join(archivePath, "2024_Q1")); !os.IsNotExist(err) {
+				t.Errorf("Expected the emptied 2024_Q1 folder to be removed")
+			}
+			if completed, ok := projectCompleted(filepath.Join(archivePath, tc.expected["2024_Q3/PROJ-2"])); !ok || completed.Format("2006-01-02") != "2024-07-01" {
+				t.Errorf("Expected PROJ-2 to be marked completed at the start of its old quarter, got %v", completed)
+			}
+
+			// Running again with the same settings finds everything already in place
+			moves, err = planArchiveRebucket(archivePath, quarters, period.Quarterly, tc.calendar, tc.bucket)
+			if err != nil {
+				t.Fatalf("Second planArchiveRebucket returned an error: %v", err)
+			}
+			if len(moves) != 0 {
+				t.Errorf("Expected a second run to move nothing, got %v", moves)
+			}
+		})
+	}
+}
+
+func TestPlanArchiveRebucketExistingTarget(t *testing.T) {
+	archivePath := t.TempDir()
+	writeArchive(t, archivePath, map[string]string{
+		"2024_Q1/PROJ-1": "2024-02-10",
+		"2024-02/PROJ-1": "2024-02-11",
+	})
+
+	quarters, _ := period.NewCalendar(1)
+	moves, err := planArchiveRebucket(archivePath, quarters, period.Quarterly, quarters, period.Monthly)
+	if err == nil {
+		t.Fatalf("Expected an error for a target that already exists, got moves %v", moves)
+	}
+
+	content, err := os.ReadFile(projectNotePath(filepath.Join(archivePath, "2024-02/PROJ-1")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "---\ncompleted: 2024-02-11\n---\n# PROJ-1\n" {
+		t.Errorf("Expected the existing project to be left alone, got %q", content)
+	}
+	if _, err := os.Stat(filepath.Join(archivePath, "2024_Q1/PROJ-1")); err != nil {
+		t.Errorf("Expected the project to stay where it was: %v", err)
+	}
+}
+
+func TestRebucketDayNotes(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		VaultPath:            tempDir,
+		DayPath:              "days",
+		FiscalYearStartMonth: 4,
+	}
+	writeNotes(t, filepath.Join(tempDir, "days"), map[string]string{
+		"2024_Q1/2-10-2024.md": "February\n",
+		"2024_Q3/7-1-2024.md":  "July\n",
+	})
+	quarters, _ := period.NewCalendar(1)
+
+	defer func() { rebucketDryRun = false }()
+
+	// A dry run only lists the moves
+	rebucketDryRun = true
+	if err := rebucketDayNotes(cfg, quarters, period.Quarterly, "Would move"); err != nil {
+		t.Fatalf("rebucketDayNotes returned an error: %v", err)
+	}
+	for _, note := range []string{"2024_Q1/2-10-2024.md", "2024_Q3/7-1-2024.md"} {
+		if _, err := os.Stat(filepath.Join(tempDir, "days", note)); err != nil {
+			t.Errorf("Expected the dry run to leave %s in place: %v", note, err)
+		}
+	}
+
+	rebucketDryRun = false
+	if err := rebucketDayNotes(cfg, quarters, period.Quarterly, "Moved"); err != nil {
+		t.Fatalf("rebucketDayNotes returned an error: %v", err)
+	}
+	expected := map[string]string{
+		"2024_Q4/2-10-2024.md": "February\n",
+		"2025_Q2/7-1-2024.md":  "July\n",
+	}
+	for note, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tempDir, "days", note))
+		if err != nil {
+			t.Errorf("Expected %s to exist: %v", note, err)
+			continue
+		}
+		if string(content) != want {
+			t.Errorf("Expected %s to hold %q, got %q", note, want, content)
+		}
+	}
+	for _, folder := range []string{"2024_Q1", "2024_Q3"} {
+		if _, err := os.Stat(filepath.Join(tempDir, "days", folder)); !os.IsNotExist(err) {
+			t.Errorf("Expected the emptied %s folder to be removed", folder)
+		}
+	}
+
+	// A second run finds every note where it belongs
+	if err := rebucketDayNotes(cfg, quarters, period.Quarterly, "Moved"); err != nil {
+		t.Fatalf("Second rebucketDayNotes returned an error: %v", err)
+	}
+	for note := range expected {
+		if _, err := os.Stat(filepath.Join(tempDir, "days", note)); err != nil {
+			t.Errorf("Expected %s to stay put on a second run: %v", note, err)
+		}
+	}
+}
+
+func TestRebucketDayNotesExistingTarget(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		VaultPath:     tempDir,
+		DayPath:       "days",
+		PeriodFolders: "month",
+	}
+	writeNotes(t, filepath.Join(tempDir, "days"), map[string]string{
+		"2024_Q1/2-10-2024.md": "Old\n",
+		"2024-02/2-10-2024.md": "New\n",
+	})
+	quarters, _ := period.NewCalendar(1)
+
+	if err := rebucketDayNotes(cfg, quarters, period.Quarterly, "Moved"); err == nil {
+		t.Fatal("Expected an error for a note that already exists at the target")
+	}
+	for note, want := range map[string]string{"2024_Q1/2-10-2024.md": "Old\n", "2024-02/2-10-2024.md": "New\n"} {
+		content, err := os.ReadFile(filepath.Join(tempDir, "days", note))
+		if err != nil || string(content) != want {
+			t.Errorf("Expected %s to still hold %q, got %q (%v)", note, want, content, err)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"gnote/config"
+	"gnote/frontmatter"
+	"gnote/period"
+	"gnote/rollup"
+	"gnote/workday"
+
+	"github.com/spf13/cobra"
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Reports built from the notes in your vault",
+}
+
+// reportEstimatesCmd represents the report estimates command
+var reportEstimatesCmd = &cobra.Command{
+	Use:   "estimates",
+	Short: "Compare ticket estimates with when they were actually done",
+	Long: `Reads the started, estimate, due and completed dates from every project and archived project
+and shows estimate vs actual per ticket, the hit rate for each estimate, and how it changes by quarter.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			return
+		}
+		calendar, err := workday.Load(expandHome(cfg.HolidaysFile))
+		if err != nil {
+			fmt.Println("Error reading holidays:", err)
+			return
+		}
+
+		records, err := readEstimateRecords(cfg)
+		if err != nil {
+			fmt.Println("Error reading estimates:", err)
+			return
+		}
+		if len(records) == 0 {
+			fmt.Println("No estimated tickets found.")
+			return
+		}
+
+		periods, _, err := periodFolders(cfg)
+		if err != nil {
+			fmt.Println("Error reading period settings:", err)
+			return
+		}
+
+		writeEstimateReport(os.Stdout, records, calendar, periods, cfg.Estimates(), time.Now())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.AddCommand(reportEstimatesCmd)
+}
+
+// readEstimateRecords collects the estimate front matter of every project, then every archived project.
+func readEstimateRecords(cfg *config.Config) ([]rollup.Estimate, error) {
+	projects, err := allProjectPaths(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []rollup.Estimate
+	for _, project := range projects {
+		record, ok, err := readEstimateRecord(project)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", project, err)
+		}
+		if ok {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// readEstimateRecord reads a project's estimate from its Estimate.md and its start and completion
+// dates from the description note. Projects created before estimates were recorded are skipped.
+func readEstimateRecord(projectPath string) (rollup.Estimate, bool, error) {
+	doc, err := frontmatter.ReadFile(projectNotePath(projectPath))
+	if os.IsNotExist(err) {
+		return rollup.Estimate{}, false, nil
+	}
+	if err != nil {
+		return rollup.Estimate{}, false, err
+	}
+
+	var meta struct {
+		Started   string `yaml:"started"`
+		Estimate  *int   `yaml:"estimate"`
+		Due       string `yaml:"due"`
+		Completed string `yaml:"completed"`
+	}
+	if err := doc.Decode(&meta); err != nil {
+		return rollup.Estimate{}, false, err
+	}
+
+	// Early tickets kept the estimate in the description note, so only override what Estimate.md has
+	estimate, err := frontmatter.ReadFile(filepath.Join(projectPath, estimateFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return rollup.Estimate{}, false, err
+	}
+	if err == nil {
+		if err := estimate.Decode(&meta); err != nil {
+			return rollup.Estimate{}, false, err
+		}
+	}
+
+	if meta.Started == "" || meta.Due == "" || meta.Estimate == nil {
+		return rollup.Estimate{}, false, nil
+	}
+
+	record := rollup.Estimate{Ticket: filepath.Base(projectPath), Estimate: *meta.Estimate}
+	if record.Started, err = time.ParseInLocation("2006-01-02", meta.Started, time.Local); err != nil {
+		return rollup.Estimate{}, false, err
+	}
+	if record.Due, err = time.ParseInLocation("2006-01-02", meta.Due, time.Local); err != nil {
+		return rollup.Estimate{}, false, err
+	}
+	if meta.Completed != "" {
+		if record.Completed, err = time.ParseInLocation("2006-01-02", meta.Completed, time.Local); err != nil {
+			return rollup.Estimate{}, false, err
+		}
+	}
+	return record, true, nil
+}
+
+func writeEstimateReport(out io.Writer, records []rollup.Estimate, calendar *workday.Calendar, periods period.Calendar, options []config.EstimateOption, today time.Time) {
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Started.Before(records[j].Started)
+	})
+
+	labels := map[int]string{}
+	for _, option := range options {
+		labels[option.Days] = option.Label
+	}
+	bucketName := func(days int) string {
+		if label, ok := labels[days]; ok {
+			return fmt.Sprintf("%s (%d)", label, days)
+		}
+		return fmt.Sprintf("%d days", days)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "TICKET\tESTIMATE\tSTARTED\tDUE\tCOMPLETED\tACTUAL\tRESULT")
+	for _, r := range records {
+		completed, actual := "-", "-"
+		var result string
+		switch {
+		case r.OnTime():
+			completed = r.Completed.Format("2006-01-02")
+			actual = fmt.Sprintf("%d", r.Actual(calendar))
+			result = "on time"
+		case r.Done():
+			completed = r.Completed.Format("2006-01-02")
+			actual = fmt.Sprintf("%d", r.Actual(calendar))
+			result = fmt.Sprintf("late by %d", calendar.WorkingDaysBetween(r.Due, r.Completed))
+		case today.After(r.Due.AddDate(0, 0, 1)):
+			result = "open, overdue"
+		default:
+			result = "open"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n", r.Ticket, r.Estimate,
+			r.Started.Format("2006-01-02"), r.Due.Format("2006-01-02"), completed, actual, result)
+	}
+	w.Flush()
+
+	buckets := rollup.TallyBy(records, calendar, func(r rollup.Estimate) int { return r.Estimate })
+	quarters := rollup.TallyBy(records, calendar, func(r rollup.Estimate) string {
+		return periods.Folder(period.Quarterly, r.Completed)
+	})
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(w, "ESTIMATE\tDONE\tON TIME\tHIT RATE\tAVG SLIP")
+	var estimates []int
+	for days := range buckets {
+		estimates = append(estimates, days)
+	}
+	sort.Ints(estimates)
+	for _, days := range estimates {
+		t := buckets[days]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\n", bucketName(days), t.Done, t.OnTime, t.HitRate(), t.AverageSlip())
+	}
+	w.Flush()
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(w, "QUARTER\tDONE\tON TIME\tHIT RATE\tAVG SLIP")
+	var quarterNames []string
+	for quarter := range quarters {
+		quarterNames = append(quarterNames, quarter)
+	}
+	sort.Strings(quarterNames)
+	for _, quarter := range quarterNames {
+		t := quarters[quarter]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\n", quarter, t.Done, t.OnTime, t.HitRate(), t.AverageSlip())
+	}
+	w.Flush()
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gnote/config"
+	"gnote/period"
+	"gnote/rollup"
+	"gnote/workday"
+)
+
+func reportDate(s string) time.Time {
+	d, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestWriteEstimateReport(t *testing.T) {
+	records := []rollup.Estimate{
+		{Ticket: "PROJ-2", Estimate: 3, Started: reportDate("2024-04-01"), Due: reportDate("2024-04-04"), Completed: reportDate("2024-04-08")},
+		{Ticket: "PROJ-1", Estimate: 1, Started: reportDate("2024-01-08"), Due: reportDate("2024-01-09"), Completed: reportDate("2024-01-09")},
+		{Ticket: "PROJ-3", Estimate: 3, Started: reportDate("2024-05-01"), Due: reportDate("2024-05-06")},
+		{Ticket: "PROJ-5", Estimate: 3, Started: reportDate("2024-01-15"), Due: reportDate("2024-01-18"), Completed: reportDate("2024-01-17")},
+		{Ticket: "PROJ-4", Estimate: 3, Started: reportDate("2024-05-16"), Due: reportDate("2024-05-21")},
+	}
+	periods, _ := period.NewCalendar(1)
+	options := []config.EstimateOption{{Label: "A little", Days: 1}, {Label: "A lot", Days: 3}}
+
+	var out bytes.Buffer
+	writeEstimateReport(&out, records, workday.New(), periods, options, reportDate("2024-05-20"))
+
+	expected := `TICKET  ESTIMATE  STARTED     DUE         COMPLETED   ACTUAL  RESULT
+PROJ-1  1         2024-01-08  2024-01-09  2024-01-09  1       on time
+PROJ-5  3         2024-01-15  2024-01-18  2024-01-17  2       on time
+PROJ-2  3         2024-04-01  2024-04-04  2024-04-08  5       late by 2
+PROJ-3  3         2024-05-01  2024-05-06  -           -       open, overdue
+PROJ-4  3         2024-05-16  2024-05-21  -           -       open
+
+ESTIMATE      DONE  ON TIME  HIT RATE  AVG SLIP
+A little (1)  1     1        100%      +0.0
+A lot (3)     2     1        50%       +0.5
+
+QUARTER  DONE  ON TIME  HIT RATE  AVG SLIP
+2024_Q1  2     2        100%      -0.5
+2024_Q2  1     0        0%        +2.0
+`
+	if out.String() != expected {
+		t.Errorf("Expected report:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestReadEstimateRecord(t *testing.T) {
+	testCases := []struct {
+		name     string
+		files    map[string]string
+		expected rollup.Estimate
+		ok       bool
+		err      bool
+	}{
+		{
+			name: "estimate file",
+			files: map[string]string{
+				"PROJ-1.md":   "---\nstarted: 2024-04-01\ncompleted: 2024-04-08\n---\n# PROJ-1\n",
+				"Estimate.md": "---\nestimate: 3\ndue: 2024-04-04\n---\n",
+			},
+			expected: rollup.Estimate{Ticket: "PROJ-1", Estimate: 3, Started: reportDate("2024-04-01"), Due: reportDate("2024-04-04"), Completed: reportDate("2024-04-08")},
+			ok:       true,
+		},
+		{
+			name: "estimate in the description",
+			files: map[string]string{
+				"PROJ-1.md": "---\nstarted: 2024-04-01\nestimate: 0\ndue: 2024-04-01\n---\n# PROJ-1\n",
+			},
+			expected: rollup.Estimate{Ticket: "PROJ-1", Estimate: 0, Started: reportDate("2024-04-01"), Due: reportDate("2024-04-01")},
+			ok:       true,
+		},
+		{
+			name: "estimate file wins",
+			files: map[string]string{
+				"PROJ-1.md":   "---\nstarted: 2024-04-01\nestimate: 1\ndue: 2024-04-02\n---\n# PROJ-1\n",
+				"Estimate.md": "---\nestimate: 3\ndue: 2024-04-04\n---\n",
+			},
+			expected: rollup.Estimate{Ticket: "PROJ-1", Estimate: 3, Started: reportDate("2024-04-01"), Due: reportDate("2024-04-04")},
+			ok:       true,
+		},
+		{
+			name: "not started",
+			files: map[string]string{
+				"PROJ-1.md":   "# PROJ-1\n",
+				"Estimate.md": "---\nestimate: 3\ndue: 2024-04-04\n---\n",
+			},
+		},
+		{
+			name:  "no description",
+			files: map[string]string{"Estimate.md": "---\nestimate: 3\ndue: 2024-04-04\n---\n"},
+		},
+		{
+			name: "bad date",
+			files: map[string]string{
+				"PROJ-1.md":   "---\nstarted: April\n---\n# PROJ-1\n",
+				"Estimate.md": "---\nestimate: 3\ndue: 2024-04-04\n---\n",
+			},
+			err: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			projectPath := filepath.Join(t.TempDir(), "PROJ-1")
+			if err := os.Mkdir(projectPath, 0755); err != nil {
+				t.Fatal(err)
+			}
+			writeNotes(t, projectPath, tc.files)
+
+			record, ok, err := readEstimateRecord(projectPath)
+			if (err != nil) != tc.err {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+			if ok != tc.ok {
+				t.Fatalf("Expected ok %v, got %v", tc.ok, ok)
+			}
+			if record != tc.expected {
+				t.Errorf("Expected %+v, got %+v", tc.expected, record)
+			}
+		})
+	}
+}
@@ -0,0 +1,336 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"gnote/checklist"
+	"gnote/clock"
+	"gnote/config"
+	"gnote/daynote"
+	"gnote/frontmatter"
+	"gnote/period"
+	"gnote/rollup"
+	"gnote/tasks"
+	"gnote/workday"
+
+	"github.com/spf13/cobra"
+)
+
+type ReviewArgs struct {
+	Name  string
+	Dates string
+	// DayNotes are the names of the period's day notes, oldest first
+	DayNotes  []string
+	Started   []rollup.Project
+	Archived  []rollup.Project
+	Estimates []ReviewEstimate
+	Tally     rollup.Tally
+	Tasks     []rollup.Task
+}
+
+// ReviewEstimate is a ticket finished in the period, with how long it took against its estimate.
+type ReviewEstimate struct {
+	Ticket   string
+	Estimate int
+	Actual   int
+	Result   string
+}
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Write a review of a month or quarter",
+}
+
+var reviewMonthCmd = &cobra.Command{
+	Use:   "month [date]",
+	Short: "Review this month, or the month of the given day",
+	Run: func(cmd *cobra.Command, args []string) {
+		runReview(period.Monthly, args)
+	},
+}
+
+var reviewQuarterCmd = &cobra.Command{
+	Use:   "quarter [date]",
+	Short: "Review this quarter, or the quarter of the given day",
+	Long: `Writes a review of the quarter, counted from fiscal_year_start_month, next to its day notes:
+the projects started and archived, how many day notes there were, the tasks ticked off and how estimates went.
+Running it again refreshes everything but the Notes section.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runReview(period.Quarterly, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reviewCmd)
+	reviewCmd.AddCommand(reviewMonthCmd)
+	reviewCmd.AddCommand(reviewQuarterCmd)
+}
+
+func runReview(bucket period.Bucket, args []string) {
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		fmt.Println("Error reading config:", err)
+		return
+	}
+	periods, archiveBucket, err := periodFolders(cfg)
+	if err != nil {
+		fmt.Println("Error reading period settings:", err)
+		return
+	}
+	calendar, err := workday.Load(expandHome(cfg.HolidaysFile))
+	if err != nil {
+		fmt.Println("Error reading holidays:", err)
+		return
+	}
+	now, err := today(cfg, clock.System)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	date, err := parseDayExpression(strings.Join(args, " "), now)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	name := periods.Folder(bucket, date)
+	start, err := periods.Start(bucket, name, date.Location())
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	months := 1
+	if bucket == period.Quarterly {
+		months = 3
+	}
+	r := rollup.Range{Start: start, End: start.AddDate(0, months, 0)}
+
+	index, err := loadDayNotes(cfg, date.Location())
+	if err != nil {
+		fmt.Println("Error reading day notes:", err)
+		return
+	}
+	projects, err := readProjects(cfg, periods, archiveBucket)
+	if err != nil {
+		fmt.Println("Error reading projects:", err)
+		return
+	}
+	estimates, err := readEstimateRecords(cfg)
+	if err != nil {
+		fmt.Println("Error reading estimates:", err)
+		return
+	}
+	items, err := checklistItems(cfg)
+	if err != nil {
+		fmt.Println("Error reading the checklist:", err)
+		return
+	}
+
+	reviewArgs, err := buildReviewArgs(name, r, index.Between(r.Start, r.Last()), projects, estimates, calendar, reviewSkippedSections(items))
+	if err != nil {
+		fmt.Println("Error reading day notes:", err)
+		return
+	}
+
+	// Next to the period's day notes, in the folder of its first day
+	dayPath, err := dayFilePath(cfg, start)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	filePath, err := writeReviewFile(filepath.Join(filepath.Dir(dayPath), name+" Review.md"), reviewArgs)
+	if err != nil {
+		fmt.Println("Error writing the review:", err)
+		return
+	}
+	openDayFile(cfg, filePath)
+}
+
+// reviewSkippedSections are the day note sections of the checklist, whose tasks keep coming back, so they're
+// left out of reviews.
+func reviewSkippedSections(items []checklist.Item) map[string]bool {
+	skipped := map[string]bool{}
+	for _, item := range items {
+		if item.Section == "" {
+			skipped[checklist.MorningChecklist] = true
+		} else {
+			skipped[item.Section] = true
+		}
+	}
+	return skipped
+}
+
+func buildReviewArgs(name string, r rollup.Range, notes []daynote.Note, projects []rollup.Project, estimates []rollup.Estimate, calendar *workday.Calendar, skipped map[string]bool) (ReviewArgs, error) {
+	args := ReviewArgs{
+		Name:     name,
+		Dates:    fmt.Sprintf("%s to %s", r.Start.Format("2 January 2006"), r.Last().Format("2 January 2006")),
+		Started:  rollup.StartedIn(projects, r),
+		Archived: rollup.ArchivedIn(projects, r),
+	}
+	for _, note := range notes {
+		args.DayNotes = append(args.DayNotes, note.Name())
+	}
+
+	noteTasks, err := rollup.Tasks(notes)
+	if err != nil {
+		return ReviewArgs{}, err
+	}
+	for _, task := range noteTasks {
+		if task.Status == tasks.Done && !skipped[task.Section] {
+			args.Tasks = append(args.Tasks, task)
+		}
+	}
+
+	for _, e := range rollup.CompletedIn(estimates, r) {
+		result := "on time"
+		if !e.OnTime() {
+			result = fmt.Sprintf("late by %d", calendar.WorkingDaysBetween(e.Due, e.Completed))
+		}
+		args.Estimates = append(args.Estimates, ReviewEstimate{
+			Ticket:   e.Ticket,
+			Estimate: e.Estimate,
+			Actual:   e.Actual(calendar),
+			Result:   result,
+		})
+		args.Tally.Add(e, calendar)
+	}
+	return args, nil
+}
+
+// readProjects reads the dates of every project and archived project. Archived projects without
+// a completed date count as completed at the start of the period folder they were archived into.
+func readProjects(cfg *config.Config, periods period.Calendar, bucket period.Bucket) ([]rollup.Project, error) {
+	projectPaths, err := allProjectPaths(cfg)
+	if err != nil {
+		return nil, err
+	}
+	archivePath := filepath.Join(cfg.VaultPath, cfg.ArchivesPath)
+
+	var projects []rollup.Project
+	for _, path := range projectPaths {
+		project := rollup.Project{
+			Name:     filepath.Base(path),
+			Path:     path,
+			Archived: strings.HasPrefix(path, archivePath+string(filepath.Separator)),
+		}
+		doc, err := frontmatter.ReadFile(projectNotePath(path))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if err == nil {
+			if value, ok := doc.Get("started"); ok {
+				project.Started, _ = time.ParseInLocation("2006-01-02", value, time.Local)
+			}
+		}
+		if completed, ok := projectCompleted(path); ok {
+			project.Completed = completed
+		} else if project.Archived {
+			project.Completed, _ = periods.Start(bucket, filepath.Base(filepath.Dir(path)), time.Local)
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
+
+// writeReviewFile creates the review, or refreshes the generated sections of an existing one.
+func writeReviewFile(filePath string, args ReviewArgs) (string, error) {
+	return filePath, writeGeneratedNote(filePath, newReviewTemplate(), args, []generatedSection{
+		{"## Day notes", "dayNotes"},
+		{"## Projects started", "started"},
+		{"## Projects archived", "archived"},
+		{"## Estimates", "estimates"},
+		{"## Completed tasks", "tasks"},
+	})
+}
+
+func newReviewTemplate() *template.Template {
+	const reviewTemplate = `# {{ .Name }} Review
+
+{{ .Dates }}
+
+## Day notes
+
+{{ template "dayNotes" . }}
+
+## Projects started
+
+{{ template "started" . }}
+
+## Projects archived
+
+{{ template "archived" . }}
+
+## Estimates
+
+{{ template "estimates" . }}
+
+## Completed tasks
+
+{{ template "tasks" . }}
+
+## Notes
+
+-
+`
+
+	const dayNotesTemplate = `
+{{- with .DayNotes -}}
+{{ len . }} day note(s), from [[{{ index . 0 }}]] to [[{{ index . (last .) }}]].
+{{- else -}}
+No day notes.
+{{- end }}`
+
+	const startedTemplate = `
+{{- range $i, $p := .Started }}
+{{- if $i }}
+{{ end }}
+{{- "" }}- [[{{ $p.Name }}]], started {{ $p.Started.Format "2006-01-02" }}{{ if $p.Archived }}, archived{{ end }}
+{{- else -}}
+No projects started.
+{{- end }}`
+
+	const archivedTemplate = `
+{{- range $i, $p := .Archived }}
+{{- if $i }}
+{{ end }}
+{{- "" }}- [[{{ $p.Name }}]], completed {{ $p.Completed.Format "2006-01-02" }}
+{{- else -}}
+No projects archived.
+{{- end }}`
+
+	const estimatesTemplate = `
+{{- if .Estimates -}}
+{{ .Tally.Done }} estimated ticket(s) finished, {{ .Tally.OnTime }} on time ({{ .Tally.HitRate }}), {{ .Tally.AverageSlip }} working days off the estimate on average.
+
+| Ticket | Estimate | Actual | Result |
+| --- | --- | --- | --- |
+{{- range .Estimates }}
+| [[{{ .Ticket }}]] | {{ .Estimate }} | {{ .Actual }} | {{ .Result }} |
+{{- end }}
+{{- else -}}
+No estimated tickets finished.
+{{- end }}`
+
+	const tasksTemplate = `
+{{- range $i, $task := .Tasks }}
+{{- if $i }}
+{{ end }}
+{{- "" }}- [x] {{ $task.Text }} ([[{{ $task.Note.Name }}]])
+{{- else -}}
+No tasks ticked off.
+{{- end }}`
+
+	funcs := template.FuncMap{"last": func(items []string) int { return len(items) - 1 }}
+	t := template.Must(template.New("review").Funcs(funcs).Parse(reviewTemplate))
+	template.Must(t.New("dayNotes").Parse(dayNotesTemplate))
+	template.Must(t.New("started").Parse(startedTemplate))
+	template.Must(t.New("archived").Parse(archivedTemplate))
+	template.Must(t.New("estimates").Parse(estimatesTemplate))
+	template.Must(t.New("tasks").Parse(tasksTemplate))
+	return t
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gnote/checklist"
+	"gnote/daynote"
+	"gnote/rollup"
+	"gnote/workday"
+)
+
+func TestMonthReview(t *testing.T) {
+	root := t.TempDir()
+	notePath := filepath.Join(root, "3-4-2024.md")
+	content := "# Monday\n\n## Morning Checklist\n\n- [x] check email\n\n## Today\n\n- [x] Fix the login\n- [ ] Write it up\n"
+	if err := os.WriteFile(notePath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	notes := []daynote.Note{{Date: time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC), Path: notePath}}
+
+	march := rollup.Range{
+		Start: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC),
+	}
+	projects := []rollup.Project{
+		{Name: "NEW-1", Started: time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC)},
+		{Name: "OLD-2", Archived: true, Started: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), Completed: time.Date(2024, time.March, 8, 0, 0, 0, 0, time.UTC)},
+	}
+	estimates := []rollup.Estimate{
+		{
+			Ticket:    "OLD-2",
+			Estimate:  3,
+			Started:   time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC),
+			Due:       time.Date(2024, time.March, 7, 0, 0, 0, 0, time.UTC),
+			Completed: time.Date(2024, time.March, 8, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	args, err := buildReviewArgs("2024-03", march, notes, projects, estimates, workday.New(), reviewSkippedSections(checklist.Default))
+	if err != nil {
+		t.Fatalf("buildReviewArgs returned an error: %v", err)
+	}
+	reviewPath := filepath.Join(root, "2024-03 Review.md")
+	if _, err := writeReviewFile(reviewPath, args); err != nil {
+		t.Fatalf("writeReviewFile returned an error: %v", err)
+	}
+
+	review, err := os.ReadFile(reviewPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# 2024-03 Review\n\n1 March 2024 to 31 March 2024\n",
+		"## Day notes\n\n1 day note(s), from [[3-4-2024]] to [[3-4-2024]].\n",
+		"## Projects started\n\n- [[NEW-1]], started 2024-03-04\n",
+		"## Projects archived\n\n- [[OLD-2]], completed 2024-03-08\n",
+		"1 estimated ticket(s) finished, 0 on time (0%), +1.0 working days off the estimate on average.",
+		"| [[OLD-2]] | 3 | 4 | late by 1 |",
+		"## Completed tasks\n\n- [x] Fix the login ([[3-4-2024]])\n\n## Notes",
+	} {
+		if !strings.Contains(string(review), want) {
+			t.Errorf("Expected the review to contain %q, but got:\n%s", want, review)
+		}
+	}
+}
@@ -5,8 +5,11 @@ package cmd
 
 import (
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -27,12 +30,49 @@ Use 'gnote [command] --help' for more information about a specific command.`,
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
+	rootCmd.SetArgs(negativeNumberArgs(os.Args[1:]))
 	err := rootCmd.Execute()
 	if err != nil {
 		os.Exit(1)
 	}
 }
 
+// negativeNumberArgs moves a negative number given to `gnote day` or its subcommands behind "--", so it
+// reaches the command as the day instead of being read as an unknown flag, which is what makes `gnote day -1`
+// mean yesterday. Other commands, and values of flags like --from -7, are left alone.
+func negativeNumberArgs(args []string) []string {
+	cmd, _, err := rootCmd.Find(args)
+	if err != nil || (cmd != dayCmd && cmd.Parent() != dayCmd) {
+		return args
+	}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return args
+		}
+		if _, err := strconv.Atoi(arg); err == nil && strings.HasPrefix(arg, "-") {
+			moved := append([]string{}, args[:i]...)
+			moved = append(moved, args[i+1:]...)
+			return append(moved, "--", arg)
+		}
+		if strings.HasPrefix(arg, "-") && !strings.Contains(arg, "=") && flagTakesValue(cmd, arg) {
+			i++ // The next argument is the flag's value, even when it's a negative number
+		}
+	}
+	return args
+}
+
+// flagTakesValue reports whether the flag, like --from or -o, is followed by its value.
+func flagTakesValue(cmd *cobra.Command, arg string) bool {
+	var flag *pflag.Flag
+	if name, ok := strings.CutPrefix(arg, "--"); ok {
+		flag = cmd.Flags().Lookup(name)
+	} else if len(arg) == 2 {
+		flag = cmd.Flags().ShorthandLookup(arg[1:])
+	}
+	return flag != nil && flag.NoOptDefVal == ""
+}
+
 func init() {
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
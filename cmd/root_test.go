@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNegativeNumberArgs(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		{args: []string{"day", "-1"}, expected: []string{"day", "--", "-1"}},
+		{args: []string{"day", "-1", "--no-create"}, expected: []string{"day", "--no-create", "--", "-1"}},
+		{args: []string{"day", "prev", "-2"}, expected: []string{"day", "prev", "--", "-2"}},
+		{args: []string{"day", "list", "--from", "-7"}, expected: []string{"day", "list", "--from", "-7"}},
+		{args: []string{"day", "+2"}, expected: []string{"day", "+2"}},
+		{args: []string{"tasks", "--from", "-7"}, expected: []string{"tasks", "--from", "-7"}},
+		{args: []string{"timesheet", "export", "--from", "-7"}, expected: []string{"timesheet", "export", "--from", "-7"}},
+		{args: []string{"x", "--", "-1"}, expected: []string{"x", "--", "-1"}},
+		{args: []string{"day", "--", "-1"}, expected: []string{"day", "--", "-1"}},
+	}
+	for _, tt := range tests {
+		if got := negativeNumberArgs(tt.args); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("negativeNumberArgs(%q) = %q, expected %q", tt.args, got, tt.expected)
+		}
+	}
+}
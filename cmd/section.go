@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"gnote/markdown"
+)
+
+// findSection returns the line range [start, end) of the section under heading, where start is the
+// heading line and end is the next heading of the same or a higher level (or the end of the document).
+func findSection(lines []string, heading string) (int, int, bool) {
+	level := markdown.HeadingLevel(heading)
+	for i, line := range lines {
+		if strings.TrimRight(line, " \t") != heading {
+			continue
+		}
+		end := len(lines)
+		for j := i + 1; j < len(lines); j++ {
+			if l := markdown.HeadingLevel(lines[j]); l > 0 && l <= level {
+				end = j
+				break
+			}
+		}
+		return i, end, true
+	}
+	return 0, 0, false
+}
+
+// upsertSection replaces the body of the section under heading, or appends the section to the end
+// of the document when the heading is not there yet.
+func upsertSection(content string, heading string, body string) string {
+	section := []string{heading, ""}
+	section = append(section, strings.Split(strings.Trim(body, "\n"), "\n")...)
+
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	start, end, found := findSection(lines, heading)
+	if !found {
+		lines = append(lines, "")
+		lines = append(lines, section...)
+		return strings.Join(lines, "\n") + "\n"
+	}
+
+	if end < len(lines) {
+		// Keep a blank line between this section and the next heading
+		section = append(section, "")
+	}
+	updated := append([]string{}, lines[:start]...)
+	updated = append(updated, section...)
+	updated = append(updated, lines[end:]...)
+	return strings.Join(updated, "\n") + "\n"
+}
+
+// generatedSection is a section of a note that's rendered from the named template.
+type generatedSection struct {
+	heading  string
+	template string
+}
+
+// writeGeneratedNote creates the note from t, or when it already exists, re-renders just the generated
+// sections so anything written in the rest of the note is kept.
+func writeGeneratedNote(filePath string, t *template.Template, data any, sections []generatedSection) error {
+	content, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return err
+		}
+		var note bytes.Buffer
+		if err := t.Execute(&note, data); err != nil {
+			return err
+		}
+		return os.WriteFile(filePath, note.Bytes(), 0644)
+	}
+	if err != nil {
+		return err
+	}
+
+	updated := string(content)
+	for _, section := range sections {
+		var body bytes.Buffer
+		if err := t.ExecuteTemplate(&body, section.template, data); err != nil {
+			return err
+		}
+		updated = upsertSection(updated, section.heading, body.String())
+	}
+	return os.WriteFile(filePath, []byte(updated), 0644)
+}
+
+// appendToSection adds text after the last line of the section under heading, leaving the rest of the
+// document as it was, or appends the section to the end of the document when it is not there yet.
+func appendToSection(content string, heading string, text string) string {
+	added := strings.Split(strings.Trim(text, "\n"), "\n")
+	lines := strings.Split(content, "\n")
+	start, end, found := findSection(lines, heading)
+	if !found {
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		if content != "" {
+			content += "\n"
+		}
+		return content + heading + "\n\n" + strings.Join(added, "\n") + "\n"
+	}
+
+	last := start
+	for i := start + 1; i < end; i++ {
+		if strings.TrimSpace(lines[i]) != "" {
+			last = i
+		}
+	}
+	if last == start {
+		// Keep a blank line between the heading and the first entry
+		added = append([]string{""}, added...)
+	}
+	if last == len(lines)-1 {
+		// The document didn't end with a newline
+		added = append(added, "")
+	}
+	updated := append([]string{}, lines[:last+1]...)
+	updated = append(updated, added...)
+	updated = append(updated, lines[last+1:]...)
+	return strings.Join(updated, "\n")
+}
+
+// sectionBody returns the text under heading, up to the next heading of the same or a higher level,
+// or "" when the heading is not there.
+func sectionBody(content string, heading string) string {
+	lines := strings.Split(content, "\n")
+	start, end, found := findSection(lines, heading)
+	if !found {
+		return ""
+	}
+	return strings.Join(lines[start+1:end], "\n")
+}
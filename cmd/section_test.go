@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestUpsertSection(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "Missing section is appended",
+			content:  "# Day\n\n- [ ]\n",
+			expected: "# Day\n\n- [ ]\n\n## Commits\n\n- new\n",
+		},
+		{
+			name:     "Existing section is replaced",
+			content:  "# Day\n\n## Commits\n\n- old\n- older\n",
+			expected: "# Day\n\n## Commits\n\n- new\n",
+		},
+		{
+			name:     "Following sections are kept",
+			content:  "# Day\n\n## Commits\n\n- old\n### [[PROJ-1]]\n\n## Notes\n\nkeep me\n",
+			expected: "# Day\n\n## Commits\n\n- new\n\n## Notes\n\nkeep me\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := upsertSection(tc.content, "## Commits", "- new")
+			if got != tc.expected {
+				t.Errorf("Expected %q, but got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestAppendToSection(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "Missing section is appended",
+			content:  "# Day\n\n- [ ]\n",
+			expected: "# Day\n\n- [ ]\n\n## Inbox\n\n- new\n",
+		},
+		{
+			name:     "Added after the last entry",
+			content:  "# Day\n\n## Inbox\n\n- old\n\n\n## Notes\n\nkeep me\n",
+			expected: "# Day\n\n## Inbox\n\n- old\n- new\n\n\n## Notes\n\nkeep me\n",
+		},
+		{
+			name:     "Empty section at the end",
+			content:  "# Day\n\n## Inbox",
+			expected: "# Day\n\n## Inbox\n\n- new\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := appendToSection(tc.content, "## Inbox", "- new")
+			if got != tc.expected {
+				t.Errorf("Expected %q, but got %q", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,339 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gnote/clock"
+	"gnote/config"
+	"gnote/tasks"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	tasksStatuses []string
+	tasksProject  string
+	tasksFrom     string
+	tasksTo       string
+	tasksTags     []string
+	tasksFormat   string
+	tasksArchived bool
+)
+
+// vaultTask is a task along with where in the vault it was found.
+type vaultTask struct {
+	tasks.Task
+	// ID is the short hash that `gnote tasks done` takes
+	ID   string
+	Path string
+	// Project is the project folder the task's note is in, empty for day notes
+	Project string
+	// Day is the date of the day note the task is in, zero for project notes
+	Day time.Time
+}
+
+// date is the day a task is filtered by: its due date, or the day of its day note.
+func (t vaultTask) date() time.Time {
+	if !t.Due.IsZero() {
+		return t.Due
+	}
+	return t.Day
+}
+
+type taskFilter struct {
+	Statuses map[tasks.Status]bool
+	Project  string
+	From     time.Time
+	To       time.Time
+	Tags     []string
+}
+
+func (f taskFilter) matches(t vaultTask) bool {
+	if len(f.Statuses) > 0 && !f.Statuses[t.Status] {
+		return false
+	}
+	if f.Project != "" && !strings.EqualFold(t.Project, f.Project) && !mentionsTicket(t.Text, f.Project) {
+		return false
+	}
+	if !f.From.IsZero() || !f.To.IsZero() {
+		date := t.date()
+		if date.IsZero() {
+			return false
+		}
+		if !f.From.IsZero() && date.Before(startOfDay(f.From)) {
+			return false
+		}
+		if !f.To.IsZero() && !date.Before(startOfDay(f.To).AddDate(0, 0, 1)) {
+			return false
+		}
+	}
+	for _, tag := range f.Tags {
+		if !t.HasTag(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// mentionsTicket reports whether the text has the ticket key in it, as a whole key so PROJ-1 isn't
+// found in PROJ-12.
+func mentionsTicket(text string, ticket string) bool {
+	for _, key := range ticketKeyPattern.FindAllString(strings.ToUpper(text), -1) {
+		if strings.EqualFold(key, ticket) {
+			return true
+		}
+	}
+	return false
+}
+
+var tasksCmd = &cobra.Command{
+	Use:   "tasks",
+	Short: "List the tasks in day notes and projects",
+	Long: `Lists checkboxes from day notes and every note in project folders: - [ ] open, - [x] done,
+- [>] deferred and - [-] cancelled. Due dates are written as 📅 2024-05-01 or due:2024-05-01, priorities with the
+Obsidian Tasks emoji or priority:high, and tags as #tags.
+
+--from and --to match the due date, or for tasks without one, the day of the day note they're in.
+--project matches the project folder a task is in, or a task that mentions the ticket.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			return
+		}
+		now, err := today(cfg, clock.System)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		filter := taskFilter{Statuses: map[tasks.Status]bool{}, Project: tasksProject, Tags: tasksTags}
+		for _, name := range tasksStatuses {
+			if name == "all" {
+				filter.Statuses = nil
+				break
+			}
+			status, ok := tasks.ParseStatus(name)
+			if !ok {
+				fmt.Printf("Unknown status %q, use open, done, deferred, cancelled or all\n", name)
+				return
+			}
+			filter.Statuses[status] = true
+		}
+		if tasksFrom != "" {
+			if filter.From, err = parseDayExpression(tasksFrom, now); err != nil {
+				fmt.Println(err)
+				return
+			}
+		}
+		if tasksTo != "" {
+			if filter.To, err = parseDayExpression(tasksTo, now); err != nil {
+				fmt.Println(err)
+				return
+			}
+		}
+
+		found, err := collectVaultTasks(cfg, now.Location(), tasksArchived)
+		if err != nil {
+			fmt.Println("Error reading tasks:", err)
+			return
+		}
+		var matched []vaultTask
+		for _, task := range found {
+			if filter.matches(task) {
+				matched = append(matched, task)
+			}
+		}
+		sortVaultTasks(matched)
+
+		switch tasksFormat {
+		case "table":
+			writeTasksTable(os.Stdout, cfg.VaultPath, matched)
+		case "json":
+			err = writeTasksJSON(os.Stdout, cfg.VaultPath, matched)
+		case "markdown", "md":
+			writeTasksMarkdown(os.Stdout, cfg.VaultPath, matched)
+		default:
+			err = fmt.Errorf("unknown format %q, use table, json or markdown", tasksFormat)
+		}
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tasksCmd)
+	tasksCmd.Flags().StringSliceVar(&tasksStatuses, "status", []string{"open"}, "Statuses to list: open, done, deferred, cancelled or all")
+	tasksCmd.Flags().StringVarP(&tasksProject, "project", "p", "", "Only tasks for this project")
+	tasksCmd.Flags().StringVar(&tasksFrom, "from", "", "Only tasks due, or noted, on or after this day")
+	tasksCmd.Flags().StringVar(&tasksTo, "to", "", "Only tasks due, or noted, on or before this day")
+	tasksCmd.Flags().StringSliceVarP(&tasksTags, "tag", "t", nil, "Only tasks with this tag; repeat for tasks with all of them")
+	tasksCmd.Flags().StringVarP(&tasksFormat, "format", "o", "table", "Output as table, json or markdown")
+	tasksCmd.Flags().BoolVar(&tasksArchived, "archived", false, "Include archived projects")
+}
+
+// collectVaultTasks reads the tasks from every day note, leaving out the recurring checklist, then every
+// note in the project folders.
+func collectVaultTasks(cfg *config.Config, loc *time.Location, includeArchived bool) ([]vaultTask, error) {
+	index, err := loadDayNotes(cfg, loc)
+	if err != nil {
+		return nil, err
+	}
+	items, err := checklistItems(cfg)
+	if err != nil {
+		return nil, err
+	}
+	skipped := reviewSkippedSections(items)
+
+	var found []vaultTask
+	for _, note := range index.Notes() {
+		noteTasks, err := tasks.ParseFile(note.Path, loc)
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range noteTasks {
+			if skipped[task.Section] {
+				continue
+			}
+			found = append(found, vaultTask{Task: task, ID: tasks.ID(relativeTo(cfg.VaultPath, note.Path), task), Path: note.Path, Day: note.Date})
+		}
+	}
+
+	projectPaths, err := allProjectPaths(cfg)
+	if err != nil {
+		return nil, err
+	}
+	archivePath := filepath.Join(cfg.VaultPath, cfg.ArchivesPath) + string(filepath.Separator)
+	for _, projectPath := range projectPaths {
+		if !includeArchived && strings.HasPrefix(projectPath, archivePath) {
+			continue
+		}
+		err := filepath.WalkDir(projectPath, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || filepath.Ext(path) != ".md" {
+				return err
+			}
+			noteTasks, err := tasks.ParseFile(path, loc)
+			if err != nil {
+				return err
+			}
+			for _, task := range noteTasks {
+				found = append(found, vaultTask{
+					Task:    task,
+					ID:      tasks.ID(relativeTo(cfg.VaultPath, path), task),
+					Path:    path,
+					Project: filepath.Base(projectPath),
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return found, nil
+}
+
+// sortVaultTasks puts tasks due soonest first, then the highest priority, then in the order they're written.
+func sortVaultTasks(found []vaultTask) {
+	sort.SliceStable(found, func(i, j int) bool {
+		a, b := found[i], found[j]
+		if !a.Due.Equal(b.Due) {
+			if a.Due.IsZero() || b.Due.IsZero() {
+				return b.Due.IsZero()
+			}
+			return a.Due.Before(b.Due)
+		}
+		return a.Priority > b.Priority
+	})
+}
+
+func formatDue(due time.Time) string {
+	if due.IsZero() {
+		return ""
+	}
+	return due.Format("2006-01-02")
+}
+
+func writeTasksTable(out io.Writer, vaultPath string, found []vaultTask) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS\tDUE\tPRIORITY\tPROJECT\tTASK\tNOTE")
+	for _, t := range found {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s:%d\n", t.ID, t.Status, formatDue(t.Due), t.Priority, t.Project,
+			t.Description, relativeTo(vaultPath, t.Path), t.Line)
+	}
+	w.Flush()
+}
+
+type taskJSON struct {
+	ID          string       `json:"id"`
+	Status      tasks.Status `json:"status"`
+	Text        string       `json:"text"`
+	Description string       `json:"description"`
+	Due         string       `json:"due,omitempty"`
+	Priority    string       `json:"priority,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	Project     string       `json:"project,omitempty"`
+	Section     string       `json:"section,omitempty"`
+	Note        string       `json:"note"`
+	Line        int          `json:"line"`
+}
+
+func writeTasksJSON(out io.Writer, vaultPath string, found []vaultTask) error {
+	items := make([]taskJSON, len(found))
+	for i, t := range found {
+		items[i] = taskJSON{
+			ID:          t.ID,
+			Status:      t.Status,
+			Text:        t.Text,
+			Description: t.Description,
+			Due:         formatDue(t.Due),
+			Priority:    t.Priority.String(),
+			Tags:        t.Tags,
+			Project:     t.Project,
+			Section:     t.Section,
+			Note:        relativeTo(vaultPath, t.Path),
+			Line:        t.Line,
+		}
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(items)
+}
+
+// writeTasksMarkdown writes the tasks as checkboxes grouped under links to their notes, ready to paste into a note.
+// Links use the path from the vault, since every project has a todo note.
+func writeTasksMarkdown(out io.Writer, vaultPath string, found []vaultTask) {
+	// Notes in the order their first task appears
+	var notes []string
+	byNote := map[string][]vaultTask{}
+	for _, t := range found {
+		if _, ok := byNote[t.Path]; !ok {
+			notes = append(notes, t.Path)
+		}
+		byNote[t.Path] = append(byNote[t.Path], t)
+	}
+
+	for i, path := range notes {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "### [[%s]]\n\n", strings.TrimSuffix(relativeTo(vaultPath, path), filepath.Ext(path)))
+		for _, t := range byNote[path] {
+			fmt.Fprintf(out, "- [%s] %s\n", t.Status.Mark(), t.Text)
+		}
+	}
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
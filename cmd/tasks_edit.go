@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gnote/clock"
+	"gnote/config"
+	"gnote/tasks"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// todoHeading is the list in a project's TODO.md that tasks are added to
+	todoHeading = "## TODO"
+	// todayHeading is the list in a day note that tasks are added to
+	todayHeading = "## What do you want to accomplish today?"
+)
+
+var (
+	addTaskProject string
+	addTaskToday   bool
+)
+
+var tasksDoneCmd = &cobra.Command{
+	Use:   "done <id>",
+	Short: "Tick off a task",
+	Long: `Ticks off the task with the ID shown by 'gnote tasks', or the start of one. Only the checkbox changes;
+the rest of the note is left exactly as it was.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			return
+		}
+		now, err := today(cfg, clock.System)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		found, err := collectVaultTasks(cfg, now.Location(), true)
+		if err != nil {
+			fmt.Println("Error reading tasks:", err)
+			return
+		}
+
+		task, err := findTask(found, args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if task.Status == tasks.Done {
+			fmt.Printf("Task '%s' is already done\n", task.Description)
+			return
+		}
+		if err := setTaskStatus(task, tasks.Done); err != nil {
+			fmt.Printf("Error ticking off '%s': %v\n", task.Description, err)
+			return
+		}
+		fmt.Printf("Task '%s' marked as done in %s\n", task.Description, relativeTo(cfg.VaultPath, task.Path))
+	},
+}
+
+var tasksAddCmd = &cobra.Command{
+	Use:   "add <text>",
+	Short: "Add a task to a project's TODO or today's note",
+	Long: `Adds an open task to the end of the TODO list in a project's TODO.md with --project, or to the
+"What do you want to accomplish today?" list in today's day note with --today, creating the note if needed.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			return
+		}
+		if (addTaskProject == "") == !addTaskToday {
+			fmt.Println("Give either --project or --today")
+			return
+		}
+		text := strings.Join(args, " ")
+
+		var notePath string
+		if addTaskToday {
+			now, err := today(cfg, clock.System)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			notePath, err = editDayNote(cfg, now, func(content string) string {
+				return string(tasks.Append([]byte(content), todayHeading, text))
+			})
+			if err != nil {
+				fmt.Println("Error adding task:", err)
+				return
+			}
+		} else {
+			projectPath := filepath.Join(cfg.VaultPath, cfg.ProjectsPath, addTaskProject)
+			if _, err := os.Stat(projectPath); err != nil {
+				fmt.Printf("Error finding project '%s': %v\n", addTaskProject, err)
+				return
+			}
+			notePath = projectFilePath(projectPath, "todo")
+			if err := appendTask(notePath, todoHeading, text); err != nil {
+				fmt.Println("Error adding task:", err)
+				return
+			}
+		}
+		fmt.Printf("Added '%s' to %s\n", text, relativeTo(cfg.VaultPath, notePath))
+	},
+}
+
+func init() {
+	tasksCmd.AddCommand(tasksDoneCmd)
+	tasksCmd.AddCommand(tasksAddCmd)
+	tasksAddCmd.Flags().StringVarP(&addTaskProject, "project", "p", "", "Add to this project's TODO.md")
+	tasksAddCmd.Flags().BoolVar(&addTaskToday, "today", false, "Add to today's day note")
+}
+
+// findTask finds the task with the ID, or the only task whose ID starts with it. Identical tasks in
+// the same note share an ID, and the first that isn't done yet is the one returned.
+func findTask(found []vaultTask, id string) (vaultTask, error) {
+	var matched []vaultTask
+	ids := map[string]bool{}
+	for _, task := range found {
+		if strings.HasPrefix(task.ID, strings.ToLower(id)) {
+			matched = append(matched, task)
+			ids[task.ID] = true
+		}
+	}
+	if len(matched) == 0 {
+		return vaultTask{}, fmt.Errorf("no task with ID '%s'", id)
+	}
+	if len(ids) > 1 {
+		return vaultTask{}, fmt.Errorf("'%s' matches %d tasks, give more of the ID", id, len(ids))
+	}
+	for _, task := range matched {
+		if task.Status != tasks.Done {
+			return task, nil
+		}
+	}
+	return matched[0], nil
+}
+
+// setTaskStatus rewrites the task's checkbox in its note, after checking the line still holds the task.
+// It holds the lock on the note's folder, like captures do.
+func setTaskStatus(task vaultTask, status tasks.Status) error {
+	var editErr error
+	err := editNote(task.Path, nil, func(content string) string {
+		stillThere := false
+		for _, t := range tasks.Parse([]byte(content), time.UTC) {
+			if t.Line == task.Line && t.Text == task.Text {
+				stillThere = true
+			}
+		}
+		if !stillThere {
+			editErr = fmt.Errorf("%s changed while reading it, try again", task.Path)
+			return content
+		}
+
+		updated, err := tasks.SetStatus([]byte(content), task.Line, status)
+		if err != nil {
+			editErr = err
+			return content
+		}
+		return string(updated)
+	})
+	if err != nil {
+		return err
+	}
+	return editErr
+}
+
+// appendTask adds an open task to the list under heading in the note, creating the note if needed.
+func appendTask(notePath string, heading string, text string) error {
+	create := func() error {
+		file, err := os.OpenFile(notePath, os.O_WRONLY|os.O_CREATE, 0644)
+		if err != nil {
+			return err
+		}
+		return file.Close()
+	}
+	return editNote(notePath, create, func(content string) string {
+		return string(tasks.Append([]byte(content), heading, text))
+	})
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gnote/config"
+	"gnote/tasks"
+)
+
+// writeNotes writes the notes, by path in the vault, creating their folders.
+func writeNotes(t *testing.T, root string, notes map[string]string) {
+	t.Helper()
+	for name, content := range notes {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestTasksProjectFilter(t *testing.T) {
+	root := t.TempDir()
+	cfg := &config.Config{VaultPath: root, DayPath: "days", ProjectsPath: "projects", ArchivesPath: "archives"}
+	writeNotes(t, root, map[string]string{
+		"projects/PROJ-1/TODO.md":  "## TODO\n\n- [ ] write the migration\n",
+		"projects/PROJ-12/TODO.md": "## TODO\n\n- [ ] review the design\n",
+		"days/2024_Q1/3-4-2024.md": "## What do you want to accomplish today?\n\n- [ ] pair on proj-1\n- [ ] chase PROJ-12\n- [ ] plan PROJ-100\n",
+	})
+
+	found, err := collectVaultTasks(cfg, time.UTC, false)
+	if err != nil {
+		t.Fatalf("collectVaultTasks returned an error: %v", err)
+	}
+	filter := taskFilter{Project: "PROJ-1"}
+	var matched []string
+	for _, task := range found {
+		if filter.matches(task) {
+			matched = append(matched, task.Text)
+		}
+	}
+	if len(matched) != 2 || matched[0] != "pair on proj-1" || matched[1] != "write the migration" {
+		t.Errorf("Expected only PROJ-1's tasks, but got %q", matched)
+	}
+}
+
+func TestTasksLeaveOutChecklist(t *testing.T) {
+	root := t.TempDir()
+	cfg := &config.Config{VaultPath: root, DayPath: "days", ProjectsPath: "projects", ArchivesPath: "archives"}
+	writeNotes(t, root, map[string]string{
+		"days/2024_Q1/3-4-2024.md": "## Morning Checklist\n\n- [ ] check email\n- [ ] time sheet\n\n## What do you want to accomplish today?\n\n- [ ] ship it\n",
+		"projects/PROJ-1/TODO.md":  "## TODO\n\n- [ ] write the migration\n",
+	})
+
+	found, err := collectVaultTasks(cfg, time.UTC, false)
+	if err != nil {
+		t.Fatalf("collectVaultTasks returned an error: %v", err)
+	}
+	if len(found) != 2 || found[0].Text != "ship it" || found[1].Text != "write the migration" {
+		t.Errorf("Expected the day's own task and the project's, but got %+v", found)
+	}
+}
+
+func TestTaskEditsHoldTheLock(t *testing.T) {
+	root := t.TempDir()
+	cfg := &config.Config{VaultPath: root, DayPath: "days", ProjectsPath: "projects", ArchivesPath: "archives"}
+	todoPath := filepath.Join(root, "projects", "PROJ-1", "TODO.md")
+	writeNotes(t, root, map[string]string{"projects/PROJ-1/TODO.md": "## TODO\n\n- [ ] write the migration\n"})
+
+	found, err := collectVaultTasks(cfg, time.UTC, false)
+	if err != nil || len(found) != 1 {
+		t.Fatalf("Expected the one task, but got %+v, %v", found, err)
+	}
+
+	// Ticking off a task while tasks are added leaves every edit in place
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := appendTask(todoPath, todoHeading, fmt.Sprintf("task %d", i)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	if err := setTaskStatus(found[0], tasks.Done); err != nil {
+		t.Errorf("setTaskStatus returned an error: %v", err)
+	}
+	wg.Wait()
+
+	content, err := os.ReadFile(todoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "- [x] write the migration\n") {
+		t.Errorf("Expected the task to be ticked off, but got:\n%s", content)
+	}
+	for i := 0; i < 10; i++ {
+		if !strings.Contains(string(content), fmt.Sprintf("- [ ] task %d\n", i)) {
+			t.Errorf("Expected task %d to be added, but got:\n%s", i, content)
+		}
+	}
+}
@@ -1,10 +1,18 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"gnote/clock"
 	"gnote/config"
+	"gnote/frontmatter"
+	"gnote/workday"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"syscall"
 	"text/template"
 	"time"
 
@@ -14,9 +22,12 @@ import (
 
 type TicketArgs struct {
 	Ticket   string
+	Type     string
 	Tag      string
 	Link     string
 	Estimate int
+	Started  time.Time
+	Due      time.Time
 }
 
 // UserInputCollector interface
@@ -25,55 +36,83 @@ type UserInputCollector interface {
 }
 
 // HuhInputCollector concrete implementation
-type HuhInputCollector struct{}
+type HuhInputCollector struct {
+	EstimateOptions []config.EstimateOption
+	// Ticket skips asking for the ticket number when it was given on the command line
+	Ticket string
+	// Type is asked for in the form when it wasn't given up front
+	Type        string
+	TicketTypes []string
+}
 
 func (h *HuhInputCollector) Collect() (TicketArgs, error) {
 	var (
-		ticket   string
-		tag      string
-		link     string
-		estimate int
+		ticket     = h.Ticket
+		ticketType = h.Type
+		tag        string
+		link       string
+		estimate   int
 	)
-	form := huh.NewForm(
-		huh.NewGroup(
-			huh.NewInput().
-				Title("What is the ticket number?").
-				Value(&ticket),
-			huh.NewInput().
-				Title("What Tag should this ticket use?").
-				Value(&link),
-			huh.NewSelect[int]().
-				Title("How much work will this take?").
-				Options(
-					huh.NewOption("None", 0),
-					huh.NewOption("A little", 1),
-					huh.NewOption("A lot", 3),
-				).
-				Value(&estimate),
-		),
+	estimateOptions := make([]huh.Option[int], len(h.EstimateOptions))
+	for i, option := range h.EstimateOptions {
+		estimateOptions[i] = huh.NewOption(option.Label, option.Days)
+	}
+	var fields []huh.Field
+	if h.Ticket == "" {
+		fields = append(fields, huh.NewInput().
+			Title("What is the ticket number?").
+			Value(&ticket))
+	}
+	if h.Type == "" {
+		ticketType = defaultTicketType
+		fields = append(fields, huh.NewSelect[string]().
+			Title("What kind of ticket is this?").
+			Options(huh.NewOptions(h.TicketTypes...)...).
+			Value(&ticketType))
+	}
+	fields = append(fields,
+		huh.NewInput().
+			Title("What Tag should this ticket use?").
+			Value(&link),
+		huh.NewSelect[int]().
+			Title("How much work will this take?").
+			Options(estimateOptions...).
+			Value(&estimate),
 	)
+	form := huh.NewForm(huh.NewGroup(fields...))
 	err := form.Run()
 	if err != nil {
 		return TicketArgs{}, err
 	}
 	tag = strings.Replace(link, " ", "_", -1)
-	return TicketArgs{ticket, tag, link, estimate}, nil
+	return TicketArgs{Ticket: ticket, Type: ticketType, Tag: tag, Link: link, Estimate: estimate}, nil
 }
 
 // FileGenerator interface
 type FileGenerator interface {
-	Generate(ticketArgs TicketArgs, cfg *config.Config) error
+	// Name is how the ticket_types config refers to the generator
+	Name() string
+	// Applies reports whether the file makes sense for this particular ticket
+	Applies(ticketArgs TicketArgs) bool
+	// Generate writes the file into projectPath, which is a staging folder while the project is created
+	Generate(ticketArgs TicketArgs, projectPath string) error
 }
 
 type TemplateInfo struct {
 	template *template.Template
 }
 
+// Applies is true for every ticket unless a generator says otherwise
+func (t TemplateInfo) Applies(ticketArgs TicketArgs) bool {
+	return true
+}
+
 // TodoFileGenerator concrete implementation
 type TodoFileGenerator struct{ TemplateInfo }
 
-func (g *TodoFileGenerator) Generate(ticketArgs TicketArgs, cfg *config.Config) error {
-	projectPath := fmt.Sprintf("%s/%s/%s", cfg.VaultPath, cfg.ProjectsPath, ticketArgs.Ticket)
+func (g *TodoFileGenerator) Name() string { return "todo" }
+
+func (g *TodoFileGenerator) Generate(ticketArgs TicketArgs, projectPath string) error {
 	todoPath := fmt.Sprintf("%s/TODO.md", projectPath)
 	return writeProjectFile(g.template, ticketArgs, todoPath)
 }
@@ -81,30 +120,35 @@ func (g *TodoFileGenerator) Generate(ticketArgs TicketArgs, cfg *config.Config)
 // DescFileGenerator concrete implementation
 type DescFileGenerator struct{ TemplateInfo }
 
-func (g *DescFileGenerator) Generate(ticketArgs TicketArgs, cfg *config.Config) error {
-	projectPath := fmt.Sprintf("%s/%s/%s", cfg.VaultPath, cfg.ProjectsPath, ticketArgs.Ticket)
+func (g *DescFileGenerator) Name() string { return "description" }
+
+func (g *DescFileGenerator) Generate(ticketArgs TicketArgs, projectPath string) error {
 	descPath := fmt.Sprintf("%s/%s.md", projectPath, ticketArgs.Ticket)
 	return writeProjectFile(g.template, ticketArgs, descPath)
 }
 
-// DescFileGenerator concrete implementation
+// InvestigationFileGenerator concrete implementation
 type InvestigationFileGenerator struct{ TemplateInfo }
 
-func (g *InvestigationFileGenerator) Generate(ticketArgs TicketArgs, cfg *config.Config) error {
-	projectPath := fmt.Sprintf("%s/%s/%s", cfg.VaultPath, cfg.ProjectsPath, ticketArgs.Ticket)
+func (g *InvestigationFileGenerator) Name() string { return "investigation" }
+
+func (g *InvestigationFileGenerator) Generate(ticketArgs TicketArgs, projectPath string) error {
 	investigationPath := fmt.Sprintf("%s/Investigation.md", projectPath)
 	return writeProjectFile(g.template, ticketArgs, investigationPath)
 }
 
-// DescFileGenerator concrete implementation
+// EstimateFileGenerator concrete implementation
 type EstimateFileGenerator struct{ TemplateInfo }
 
-func (g *EstimateFileGenerator) Generate(ticketArgs TicketArgs, cfg *config.Config) error {
-	timeNow := time.Now()
-	dueDate := timeNow.AddDate(0, 0, ticketArgs.Estimate)
-	formattedDueDate := fmt.Sprintf("%s, %d %s %d\n", dueDate.Weekday(), dueDate.Day(), dueDate.Month().String(), dueDate.Year())
-	projectPath := fmt.Sprintf("%s/%s/%s", cfg.VaultPath, cfg.ProjectsPath, ticketArgs.Ticket)
-	estimatePath := fmt.Sprintf("%s/%s.md", projectPath, strings.TrimRight(formattedDueDate, " \t\n"))
+func (g *EstimateFileGenerator) Name() string { return "estimate" }
+
+// Applies skips the estimate when the answer was "None", which would only be a note due today
+func (g *EstimateFileGenerator) Applies(ticketArgs TicketArgs) bool {
+	return ticketArgs.Estimate > 0
+}
+
+func (g *EstimateFileGenerator) Generate(ticketArgs TicketArgs, projectPath string) error {
+	estimatePath := fmt.Sprintf("%s/%s", projectPath, estimateFileName)
 	return writeProjectFile(g.template, ticketArgs, estimatePath)
 }
 
@@ -118,62 +162,295 @@ func NewProjectCreator(cfg *config.Config, fileGenerators []FileGenerator) *Proj
 	return &ProjectCreator{cfg: cfg, fileGenerators: fileGenerators}
 }
 
+// CreateProject builds the project in a hidden staging folder next to where it will live and only moves it
+// into place once every generator has succeeded, so a failure never leaves a half-populated project behind.
+// An existing project, even an empty folder, is never replaced.
 func (pc *ProjectCreator) CreateProject(ticketArgs TicketArgs) error {
-	projectPath := fmt.Sprintf("%s/%s/%s", pc.cfg.VaultPath, pc.cfg.ProjectsPath, ticketArgs.Ticket)
-	err := createProjectFolder(projectPath)
+	projectsPath := filepath.Join(pc.cfg.VaultPath, pc.cfg.ProjectsPath)
+	projectPath := filepath.Join(projectsPath, ticketArgs.Ticket)
+	exists := fmt.Errorf("project %s already exists", projectPath)
+	if _, err := os.Lstat(projectPath); err == nil {
+		return exists
+	}
+
+	stagingPath, err := os.MkdirTemp(projectsPath, "."+ticketArgs.Ticket+"-*")
 	if err != nil {
 		return err
 	}
+	defer os.RemoveAll(stagingPath) // Nothing left to remove once it has been renamed
+	if err := os.Chmod(stagingPath, 0755); err != nil {
+		return err
+	}
 
-	for _, generator := range pc.fileGenerators {
-		err := generator.Generate(ticketArgs, pc.cfg)
+	for _, generator := range pc.generatorsFor(ticketArgs) {
+		err := generator.Generate(ticketArgs, stagingPath)
 		if err != nil {
-			return err
+			return fmt.Errorf("%s: %w", generator.Name(), err)
 		}
 	}
 
+	// Check again, as the project may have been made while the files were generated
+	if _, err := os.Lstat(projectPath); err == nil {
+		return exists
+	}
+	if err := os.Rename(stagingPath, projectPath); err != nil {
+		if errors.Is(err, fs.ErrExist) || errors.Is(err, syscall.ENOTEMPTY) {
+			return exists
+		}
+		return err
+	}
 	return nil
 }
 
+// EnsureProject adds whatever files the ticket should have but doesn't, leaving existing files alone,
+// and returns the names of the files it added. A project that doesn't exist yet is created.
+func (pc *ProjectCreator) EnsureProject(ticketArgs TicketArgs) ([]string, error) {
+	projectPath := filepath.Join(pc.cfg.VaultPath, pc.cfg.ProjectsPath, ticketArgs.Ticket)
+	before, err := os.ReadDir(projectPath)
+	if os.IsNotExist(err) {
+		if err := pc.CreateProject(ticketArgs); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	} else {
+		for _, generator := range pc.generatorsFor(ticketArgs) {
+			err := generator.Generate(ticketArgs, projectPath)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", generator.Name(), err)
+			}
+		}
+	}
+
+	after, err := os.ReadDir(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	existed := map[string]bool{}
+	for _, entry := range before {
+		existed[entry.Name()] = true
+	}
+	var added []string
+	for _, entry := range after {
+		if !existed[entry.Name()] {
+			added = append(added, entry.Name())
+		}
+	}
+	return added, nil
+}
+
+// generatorsFor picks the generators configured for the ticket's type that apply to it.
+func (pc *ProjectCreator) generatorsFor(ticketArgs TicketArgs) []FileGenerator {
+	var files []string
+	for _, file := range pc.cfg.TicketFiles(ticketArgs.Type) {
+		files = append(files, file.GeneratorName())
+	}
+	var generators []FileGenerator
+	for _, generator := range pc.fileGenerators {
+		if slices.Contains(files, generator.Name()) && generator.Applies(ticketArgs) {
+			generators = append(generators, generator)
+		}
+	}
+	return generators
+}
+
+const defaultTicketType = "feature"
+
+var (
+	ticketType   string
+	ensureTicket bool
+)
+
 // ticketCmd represents the ticket command
 var ticketCmd = &cobra.Command{
-	Use:   "ticket",
+	Use:   "ticket [id]",
 	Short: "Create a new project folder populated with default files",
-	Long: `Follow a prompt to create a project folder containing:
-  1. Project description file
-  2. Investigation file
-  3. TODO file
-  4. Possibly, Estimate file
+	Long: `Follow a prompt to create a project folder. Which files it contains depends on the ticket type:
+  feature:  description, TODO, investigation and, possibly, estimate
+  bug:      description, TODO, repro, root cause and, possibly, estimate
+  spike:    description, question, findings and, possibly, estimate
+  incident: description, TODO, timeline and root cause
+The files for each type can be changed with ticket_types in the config.
+
+With --ensure, an existing ticket folder gets whatever files it is missing, for example
+after new files were added to its type, and the description is opened in the editor.
   `,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg, err := config.ReadConfig()
 		if err != nil {
 			fmt.Println("Error reading config:", err)
 			return
 		}
-		collector := &HuhInputCollector{}
+		calendar, err := workday.Load(expandHome(cfg.HolidaysFile))
+		if err != nil {
+			fmt.Println("Error reading holidays:", err)
+			return
+		}
+		if ticketType != "" && !slices.Contains(cfg.TicketTypeNames(), ticketType) {
+			fmt.Printf("Unknown ticket type '%s', expected one of: %s\n", ticketType, strings.Join(cfg.TicketTypeNames(), ", "))
+			return
+		}
+
+		var ticket string
+		if len(args) == 1 {
+			ticket = args[0]
+		}
+		if ensureTicket {
+			if ticket == "" {
+				fmt.Println("--ensure needs the ticket id, e.g. gnote ticket PROJ-1 --ensure")
+				return
+			}
+			ensureProject(cfg, calendar, ticket)
+			return
+		}
+		if _, err := os.Stat(filepath.Join(cfg.VaultPath, cfg.ProjectsPath, ticket)); ticket != "" && err == nil {
+			fmt.Printf("Ticket '%s' already exists, use --ensure to add any missing files\n", ticket)
+			return
+		}
+
+		collector := &HuhInputCollector{
+			EstimateOptions: cfg.Estimates(),
+			Ticket:          ticket,
+			Type:            ticketType,
+			TicketTypes:     cfg.TicketTypeNames(),
+		}
 		ticketArgs, err := collector.Collect()
 		if err != nil {
 			fmt.Println(err)
 			return
 		}
+		if err := checkTicketID(cmd, ticketArgs.Ticket); err != nil {
+			fmt.Println(err)
+			return
+		}
+		ticketArgs = scheduleTicket(ticketArgs, calendar, clock.System)
 
-		todoGenerator := &TodoFileGenerator{TemplateInfo{todoTemplate()}}
-		descGenerator := &DescFileGenerator{TemplateInfo{descTemplate()}}
-		estimateGenerator := &EstimateFileGenerator{TemplateInfo{estimateTemplate()}}
-		investigationGenerator := &InvestigationFileGenerator{TemplateInfo{investigationTemplate()}}
-
-		creator := NewProjectCreator(cfg, []FileGenerator{todoGenerator, descGenerator, estimateGenerator, investigationGenerator})
+		generators, err := ticketGenerators(cfg, ticketArgs.Type)
+		if err != nil {
+			fmt.Println("Error reading ticket templates:", err)
+			return
+		}
+		creator := NewProjectCreator(cfg, generators)
 
 		err = creator.CreateProject(ticketArgs)
 		if err != nil {
 			fmt.Println(err)
+			return
+		}
+
+		editor := newEditor(cfg)
+		projectPath := filepath.Join(cfg.VaultPath, cfg.ProjectsPath, ticketArgs.Ticket)
+		if err := editor.OpenFile(projectFilePath(projectPath, "description")); err != nil {
+			fmt.Printf("Failed to open file in editor: %s\n", err)
 		}
 	},
 }
 
+// scheduleTicket starts the ticket now and makes it due once its estimate's working days are up.
+func scheduleTicket(ticketArgs TicketArgs, calendar *workday.Calendar, c clock.Clock) TicketArgs {
+	ticketArgs.Started = c.Now()
+	ticketArgs.Due = calendar.AddWorkingDays(ticketArgs.Started, ticketArgs.Estimate)
+	return ticketArgs
+}
+
+// checkTicketID turns away ids that name one of the ticket command's subcommands, since
+// `gnote ticket done` runs the subcommand rather than reaching a ticket called done.
+func checkTicketID(ticketCmd *cobra.Command, id string) error {
+	for _, sub := range ticketCmd.Commands() {
+		if sub.Name() == id || sub.HasAlias(id) {
+			return fmt.Errorf("'%s' can't be a ticket id, it's taken by 'gnote ticket %s'", id, sub.Name())
+		}
+	}
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(ticketCmd)
+	ticketCmd.Flags().StringVar(&ticketType, "type", "", "Ticket type: bug, feature, spike, incident or one from your config")
+	ticketCmd.Flags().BoolVar(&ensureTicket, "ensure", false, "Add any missing files to an existing ticket folder and open its description")
+}
+
+func ensureProject(cfg *config.Config, calendar *workday.Calendar, ticket string) {
+	projectPath := filepath.Join(cfg.VaultPath, cfg.ProjectsPath, ticket)
+	ticketArgs, err := existingTicketArgs(projectPath, calendar)
+	if err != nil {
+		fmt.Println("Error reading ticket:", err)
+		return
+	}
+	if ticketType != "" {
+		ticketArgs.Type = ticketType
+	}
+
+	generators, err := ticketGenerators(cfg, ticketArgs.Type)
+	if err != nil {
+		fmt.Println("Error reading ticket templates:", err)
+		return
+	}
+	added, err := NewProjectCreator(cfg, generators).EnsureProject(ticketArgs)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if len(added) == 0 {
+		fmt.Printf("Ticket '%s' already has every file\n", ticket)
+	}
+	for _, file := range added {
+		fmt.Printf("Added %s\n", filepath.Join(projectPath, file))
+	}
+
+	editor := newEditor(cfg)
+	if err := editor.OpenFile(projectNotePath(projectPath)); err != nil {
+		fmt.Printf("Failed to open file in editor: %s\n", err)
+	}
+}
+
+// existingTicketArgs rebuilds a ticket's arguments from the front matter its files were written with,
+// falling back to a new ticket of the default type when they're missing.
+func existingTicketArgs(projectPath string, calendar *workday.Calendar) (TicketArgs, error) {
+	ticketArgs := TicketArgs{
+		Ticket:  filepath.Base(projectPath),
+		Type:    defaultTicketType,
+		Started: time.Now(),
+	}
+
+	var meta struct {
+		Type     string   `yaml:"type"`
+		Tags     []string `yaml:"tags"`
+		Link     string   `yaml:"link"`
+		Started  string   `yaml:"started"`
+		Estimate int      `yaml:"estimate"`
+		Due      string   `yaml:"due"`
+	}
+	for _, path := range []string{projectNotePath(projectPath), filepath.Join(projectPath, estimateFileName)} {
+		doc, err := frontmatter.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return TicketArgs{}, err
+		}
+		if err := doc.Decode(&meta); err != nil {
+			return TicketArgs{}, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	if meta.Type != "" {
+		ticketArgs.Type = meta.Type
+	}
+	if len(meta.Tags) > 0 {
+		ticketArgs.Tag = meta.Tags[0]
+	}
+	ticketArgs.Link = strings.TrimSuffix(strings.TrimPrefix(meta.Link, "[["), "]]")
+	if started, err := time.ParseInLocation("2006-01-02", meta.Started, time.Local); err == nil {
+		ticketArgs.Started = started
+	}
+	ticketArgs.Estimate = meta.Estimate
+	ticketArgs.Due = calendar.AddWorkingDays(ticketArgs.Started, ticketArgs.Estimate)
+	if due, err := time.ParseInLocation("2006-01-02", meta.Due, time.Local); err == nil {
+		ticketArgs.Due = due
+	}
+	return ticketArgs, nil
 }
 
 func investigationTemplate() *template.Template {
@@ -195,8 +472,15 @@ func investigationTemplate() *template.Template {
 	return template.Must(template.New("DescTemplate").Parse(tmpl))
 }
 
+const estimateFileName = "Estimate.md"
+
 func estimateTemplate() *template.Template {
-	const tmpl = `# [[{{.Ticket}}]] - Estimate: {{.Estimate}}/3
+	const tmpl = `---
+estimate: {{.Estimate}}
+due: {{.Due.Format "2006-01-02"}}
+---
+
+# [[{{.Ticket}}]] - Estimate: {{.Estimate}} working day(s), due {{.Due.Format "Monday, 2 January 2006"}}
 
 ## If this ticket was not completed by the date estimated, please describe why.
 
@@ -215,10 +499,12 @@ func estimateTemplate() *template.Template {
 func descTemplate() *template.Template {
 	const tmpl = `---
 id: {{.Ticket}} 
+type: {{.Type}}
 aliases: 
 tags:
   - '{{.Tag}}'
 link: "[[{{.Link}}]]"
+started: {{.Started.Format "2006-01-02"}}
 ---
 
 # [[{{.Ticket}}]]
@@ -246,22 +532,32 @@ func todoTemplate() *template.Template {
 	return template.Must(template.New("TodoTemplate").Parse(todoTemplate))
 }
 
+// writeProjectFile renders the template to fpath unless the file already exists. The content goes to a
+// temporary file first and is renamed into place, so fpath is never left half written.
 func writeProjectFile(ticketT *template.Template, ticketArgs TicketArgs, fpath string) error {
-	var file *os.File
 	_, err := os.Stat(fpath)
-	if os.IsNotExist(err) {
-		file, _ = os.Create(fpath)
-		defer file.Close()
-		return ticketT.Execute(file, ticketArgs)
-	} else {
+	if err == nil {
 		return nil
 	}
-}
+	if !os.IsNotExist(err) {
+		return err
+	}
 
-func createProjectFolder(projectPath string) error {
-	err := os.Mkdir(projectPath, os.ModePerm)
+	file, err := os.CreateTemp(filepath.Dir(fpath), "."+filepath.Base(fpath)+"-*")
 	if err != nil {
 		return err
 	}
-	return nil
+	defer os.Remove(file.Name()) // Fails harmlessly once renamed
+
+	if err := ticketT.Execute(file, ticketArgs); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(file.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), fpath)
 }
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gnote/config"
+	"gnote/frontmatter"
+
+	"github.com/spf13/cobra"
+)
+
+// ticketDoneCmd represents the ticket done command
+var ticketDoneCmd = &cobra.Command{
+	Use:   "done [ticket]",
+	Short: "Mark a ticket as completed",
+	Long:  `Records today as the completion date in the ticket's description front matter, so 'gnote report estimates' can compare it against the estimate.`,
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			return
+		}
+		projectsPath := filepath.Join(cfg.VaultPath, cfg.ProjectsPath)
+
+		var ticket string
+		if len(args) == 1 {
+			ticket = args[0]
+		} else {
+			projectFolders, err := listProjectFolders(projectsPath)
+			if err != nil {
+				fmt.Println("Error listing project folders:", err)
+				return
+			}
+			if len(projectFolders) == 0 {
+				fmt.Println("No projects found.")
+				return
+			}
+			ticket, err = Picker{
+				Title: "Select the completed ticket:",
+				Items: projectPickerItems(projectsPath, projectFolders),
+			}.PickOne()
+			if err != nil {
+				fmt.Println("Error during selection:", err)
+				return
+			}
+		}
+
+		projectPath := filepath.Join(projectsPath, ticket)
+		if _, err := os.Stat(projectNotePath(projectPath)); err != nil {
+			fmt.Printf("Error finding ticket '%s': %v\n", ticket, err)
+			return
+		}
+		if err := markCompleted(projectPath, time.Now()); err != nil {
+			fmt.Printf("Error marking '%s' as done: %v\n", ticket, err)
+			return
+		}
+		fmt.Printf("Ticket '%s' marked as done\n", ticket)
+	},
+}
+
+func init() {
+	ticketCmd.AddCommand(ticketDoneCmd)
+}
+
+// projectNotePath is the description note that DescFileGenerator writes, named after the project folder.
+func projectNotePath(projectPath string) string {
+	return filepath.Join(projectPath, filepath.Base(projectPath)+".md")
+}
+
+// markCompleted records the completion date in the project's description note.
+// An existing completion date is kept, and projects without a description note are left alone.
+func markCompleted(projectPath string, completed time.Time) error {
+	notePath := projectNotePath(projectPath)
+	doc, err := frontmatter.ReadFile(notePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if value, ok := doc.Get("completed"); ok && value != "" {
+		return nil
+	}
+	doc.Set("completed", completed.Format("2006-01-02"))
+	return doc.WriteFile(notePath)
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"gnote/config"
+	"gnote/frontmatter"
+
+	"github.com/spf13/cobra"
+)
+
+var migrateDryRun bool
+
+// ticketMigrateCmd represents the ticket migrate command
+var ticketMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Rename date-named estimate files to Estimate.md",
+	Long: `Older tickets have their estimate file named after the due date, like 'Friday, 3 May 2024.md'.
+This renames them to Estimate.md across projects and archives, recording the estimate, due date and start date in front matter.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			return
+		}
+
+		projects, err := allProjectPaths(cfg)
+		if err != nil {
+			fmt.Println("Error listing projects:", err)
+			return
+		}
+
+		migrated := 0
+		for _, project := range projects {
+			legacyName, due, found, err := findLegacyEstimateFile(project)
+			if err != nil {
+				fmt.Printf("Error reading '%s': %v\n", project, err)
+				continue
+			}
+			if !found {
+				continue
+			}
+
+			if migrateDryRun {
+				fmt.Printf("Would move %s to %s\n", filepath.Join(project, legacyName), estimateFileName)
+				migrated++
+				continue
+			}
+			if err := migrateEstimateFile(project, legacyName, due); err != nil {
+				fmt.Printf("Error migrating '%s': %v\n", filepath.Join(project, legacyName), err)
+				continue
+			}
+			fmt.Printf("Moved %s to %s\n", filepath.Join(project, legacyName), estimateFileName)
+			migrated++
+		}
+		fmt.Printf("%d estimate file(s) migrated\n", migrated)
+	},
+}
+
+func init() {
+	ticketCmd.AddCommand(ticketMigrateCmd)
+	ticketMigrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Only list the files that would be renamed")
+}
+
+// allProjectPaths lists every project folder, then every archived project folder.
+func allProjectPaths(cfg *config.Config) ([]string, error) {
+	projectsPath := filepath.Join(cfg.VaultPath, cfg.ProjectsPath)
+	folders, err := listProjectFolders(projectsPath)
+	if err != nil {
+		return nil, err
+	}
+	var projects []string
+	for _, folder := range folders {
+		projects = append(projects, filepath.Join(projectsPath, folder))
+	}
+
+	archived, err := listArchivedProjects(filepath.Join(cfg.VaultPath, cfg.ArchivesPath))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return append(projects, archived...), nil
+}
+
+const legacyEstimateLayout = "Monday, 2 January 2006.md"
+
+// findLegacyEstimateFile looks for an estimate file named after its due date.
+func findLegacyEstimateFile(projectPath string) (string, time.Time, bool, error) {
+	entries, err := os.ReadDir(projectPath)
+	if err != nil {
+		return "", time.Time{}, false, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		due, err := time.ParseInLocation(legacyEstimateLayout, entry.Name(), time.Local)
+		if err == nil {
+			return entry.Name(), due, true, nil
+		}
+	}
+	return "", time.Time{}, false, nil
+}
+
+var legacyEstimateHeading = regexp.MustCompile(`(?m)^# .* - Estimate: (\d+)`)
+
+// migrateEstimateFile writes the legacy estimate file's content to Estimate.md with estimate, due and
+// started front matter, and only removes the old file once the new one is safely written. Legacy tickets
+// didn't record when they started, so unless the description note has it, it's taken to be when the
+// estimate file was last written, which is usually when the ticket was created.
+func migrateEstimateFile(projectPath string, legacyName string, due time.Time) error {
+	legacyPath := filepath.Join(projectPath, legacyName)
+	info, err := os.Stat(legacyPath)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(legacyPath)
+	if err != nil {
+		return err
+	}
+
+	doc := frontmatter.Parse(content)
+	if _, ok := doc.Get("estimate"); !ok {
+		if match := legacyEstimateHeading.FindSubmatch(doc.Body()); match != nil {
+			doc.Set("estimate", string(match[1]))
+		}
+	}
+	if _, ok := doc.Get("due"); !ok {
+		doc.Set("due", due.Format("2006-01-02"))
+	}
+	if _, ok := doc.Get("started"); !ok {
+		description, err := frontmatter.ReadFile(projectNotePath(projectPath))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		recorded := ""
+		if description != nil {
+			recorded, _ = description.Get("started")
+		}
+		if recorded == "" {
+			started := info.ModTime()
+			if started.After(due) {
+				started = due
+			}
+			doc.Set("started", started.Format("2006-01-02"))
+		}
+	}
+
+	// Written aside and linked into place, so a failed write never leaves a partial Estimate.md
+	// and one that already exists is never overwritten
+	file, err := os.CreateTemp(projectPath, "."+estimateFileName+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(doc.Bytes()); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(file.Name(), 0644); err != nil {
+		return err
+	}
+	if err := os.Link(file.Name(), filepath.Join(projectPath, estimateFileName)); err != nil {
+		return err
+	}
+	return os.Remove(legacyPath)
+}
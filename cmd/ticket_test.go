@@ -0,0 +1,426 @@
+package cmd
+
+import (
+	"errors"
+	"gnote/clock"
+	"gnote/config"
+	"gnote/workday"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func defaultGenerators() []FileGenerator {
+	return []FileGenerator{
+		&TodoFileGenerator{TemplateInfo{todoTemplate()}},
+		&DescFileGenerator{TemplateInfo{descTemplate()}},
+		&EstimateFileGenerator{TemplateInfo{estimateTemplate()}},
+		&InvestigationFileGenerator{TemplateInfo{investigationTemplate()}},
+	}
+}
+
+func ticketFiles(names ...string) config.TicketType {
+	files := make([]config.TicketFile, len(names))
+	for i, name := range names {
+		files[i] = config.TicketFile{Name: name}
+	}
+	return config.TicketType{Files: files}
+}
+
+func projectFiles(t *testing.T, projectPath string) []string {
+	entries, err := os.ReadDir(projectPath)
+	if err != nil {
+		t.Fatalf("Failed to read project folder: %v", err)
+	}
+	var files []string
+	for _, entry := range entries {
+		files = append(files, entry.Name())
+	}
+	sort.Strings(files)
+	return files
+}
+
+func TestCreateProjectSelectsGenerators(t *testing.T) {
+	ticketTypes := map[string]config.TicketType{
+		"bug":   ticketFiles("todo", "description", "investigation"),
+		"spike": ticketFiles("description", "estimate"),
+	}
+
+	testCases := []struct {
+		name     string
+		args     TicketArgs
+		expected []string
+	}{
+		{
+			name:     "Unconfigured type gets the feature files",
+			args:     TicketArgs{Ticket: "PROJ-1", Type: "feature", Estimate: 3},
+			expected: []string{"Estimate.md", "Investigation.md", "PROJ-1.md", "TODO.md"},
+		},
+		{
+			name:     "Estimate of None skips the estimate file",
+			args:     TicketArgs{Ticket: "PROJ-2", Type: "feature", Estimate: 0},
+			expected: []string{"Investigation.md", "PROJ-2.md", "TODO.md"},
+		},
+		{
+			name:     "Bug only gets its configured files",
+			args:     TicketArgs{Ticket: "PROJ-3", Type: "bug", Estimate: 1},
+			expected: []string{"Investigation.md", "PROJ-3.md", "TODO.md"},
+		},
+		{
+			name:     "Configured files still have to apply",
+			args:     TicketArgs{Ticket: "PROJ-4", Type: "spike", Estimate: 0},
+			expected: []string{"PROJ-4.md"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &config.Config{
+				VaultPath:    t.TempDir(),
+				ProjectsPath: "projects",
+				TicketTypes:  ticketTypes,
+			}
+			if err := os.MkdirAll(filepath.Join(cfg.VaultPath, cfg.ProjectsPath), 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			tc.args.Started = time.Date(2024, time.May, 3, 9, 0, 0, 0, time.UTC)
+			tc.args.Due = tc.args.Started.AddDate(0, 0, tc.args.Estimate)
+
+			creator := NewProjectCreator(cfg, defaultGenerators())
+			if err := creator.CreateProject(tc.args); err != nil {
+				t.Fatalf("CreateProject returned an error: %v", err)
+			}
+
+			files := projectFiles(t, filepath.Join(cfg.VaultPath, cfg.ProjectsPath, tc.args.Ticket))
+			if len(files) != len(tc.expected) {
+				t.Fatalf("Expected files %v, but got %v", tc.expected, files)
+			}
+			for i := range files {
+				if files[i] != tc.expected[i] {
+					t.Errorf("Expected files %v, but got %v", tc.expected, files)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestCreateProjectForTicketType(t *testing.T) {
+	templatePath := filepath.Join(t.TempDir(), "checklist.md")
+	if err := os.WriteFile(templatePath, []byte("# [[{{.Ticket}}]] - Release checklist\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name        string
+		ticketTypes map[string]config.TicketType
+		args        TicketArgs
+		expected    []string
+	}{
+		{
+			name:     "Built-in bug files",
+			args:     TicketArgs{Ticket: "BUG-1", Type: "bug", Estimate: 1},
+			expected: []string{"BUG-1.md", "Estimate.md", "Repro.md", "Root Cause.md", "TODO.md"},
+		},
+		{
+			name:     "Built-in spike files",
+			args:     TicketArgs{Ticket: "SPIKE-1", Type: "spike"},
+			expected: []string{"Findings.md", "Question.md", "SPIKE-1.md"},
+		},
+		{
+			name:     "Built-in incident files",
+			args:     TicketArgs{Ticket: "INC-1", Type: "incident"},
+			expected: []string{"INC-1.md", "Root Cause.md", "TODO.md", "Timeline.md"},
+		},
+		{
+			name: "Templated file from config",
+			ticketTypes: map[string]config.TicketType{
+				"release": {Files: []config.TicketFile{
+					{Name: "description"},
+					{File: "{{.Ticket}} Checklist.md", Template: templatePath},
+				}},
+			},
+			args:     TicketArgs{Ticket: "REL-1", Type: "release"},
+			expected: []string{"REL-1 Checklist.md", "REL-1.md"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &config.Config{
+				VaultPath:    t.TempDir(),
+				ProjectsPath: "projects",
+				TicketTypes:  tc.ticketTypes,
+			}
+			if err := os.MkdirAll(filepath.Join(cfg.VaultPath, cfg.ProjectsPath), 0755); err != nil {
+				t.Fatal(err)
+			}
+			tc.args.Started = time.Date(2024, time.May, 3, 9, 0, 0, 0, time.UTC)
+			tc.args.Due = tc.args.Started.AddDate(0, 0, tc.args.Estimate)
+
+			generators, err := ticketGenerators(cfg, tc.args.Type)
+			if err != nil {
+				t.Fatalf("ticketGenerators returned an error: %v", err)
+			}
+			if err := NewProjectCreator(cfg, generators).CreateProject(tc.args); err != nil {
+				t.Fatalf("CreateProject returned an error: %v", err)
+			}
+
+			files := projectFiles(t, filepath.Join(cfg.VaultPath, cfg.ProjectsPath, tc.args.Ticket))
+			if len(files) != len(tc.expected) {
+				t.Fatalf("Expected files %v, but got %v", tc.expected, files)
+			}
+			for i := range files {
+				if files[i] != tc.expected[i] {
+					t.Errorf("Expected files %v, but got %v", tc.expected, files)
+					break
+				}
+			}
+		})
+	}
+}
+
+// failingGenerator always fails, after writing its file, to check nothing it wrote survives
+type failingGenerator struct{ name string }
+
+func (g failingGenerator) Name() string                       { return g.name }
+func (g failingGenerator) Applies(ticketArgs TicketArgs) bool { return true }
+func (g failingGenerator) Generate(ticketArgs TicketArgs, projectPath string) error {
+	if err := os.WriteFile(filepath.Join(projectPath, "partial.md"), []byte("partial"), 0644); err != nil {
+		return err
+	}
+	return errors.New("generator failed")
+}
+
+func TestCreateProjectRollsBack(t *testing.T) {
+	cfg := &config.Config{
+		VaultPath:    t.TempDir(),
+		ProjectsPath: "projects",
+		TicketTypes: map[string]config.TicketType{
+			"feature": ticketFiles("todo", "description", "broken", "investigation"),
+		},
+	}
+	projectsPath := filepath.Join(cfg.VaultPath, cfg.ProjectsPath)
+	if err := os.MkdirAll(projectsPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	args := TicketArgs{Ticket: "PROJ-1", Type: "feature"}
+
+	generators := append(defaultGenerators()[:2], failingGenerator{"broken"}, defaultGenerators()[3])
+	err := NewProjectCreator(cfg, generators).CreateProject(args)
+	if err == nil {
+		t.Fatal("Expected CreateProject to return the generator's error")
+	}
+
+	// Neither the project nor the staging folder should be left behind
+	if _, err := os.Stat(filepath.Join(projectsPath, "PROJ-1")); !os.IsNotExist(err) {
+		t.Errorf("Expected no project folder, but got %v", err)
+	}
+	if staging, _ := filepath.Glob(filepath.Join(projectsPath, ".PROJ-1-*")); len(staging) != 0 {
+		t.Errorf("Expected no staging folder, but got %v", staging)
+	}
+	if files := projectFiles(t, projectsPath); len(files) != 0 {
+		t.Fatalf("Expected the projects folder to be empty, but got %v", files)
+	}
+
+	// Running it again once the problem is fixed should work
+	cfg.TicketTypes = nil
+	if err := NewProjectCreator(cfg, defaultGenerators()).CreateProject(args); err != nil {
+		t.Fatalf("CreateProject returned an error on retry: %v", err)
+	}
+	expected := []string{"Investigation.md", "PROJ-1.md", "TODO.md"}
+	files := projectFiles(t, filepath.Join(projectsPath, "PROJ-1"))
+	if len(files) != len(expected) {
+		t.Fatalf("Expected files %v, but got %v", expected, files)
+	}
+	if files := projectFiles(t, projectsPath); len(files) != 1 {
+		t.Errorf("Expected only the project folder, but got %v", files)
+	}
+}
+
+func TestCreateProjectExisting(t *testing.T) {
+	cfg := &config.Config{VaultPath: t.TempDir(), ProjectsPath: "projects"}
+	projectPath := filepath.Join(cfg.VaultPath, cfg.ProjectsPath, "PROJ-1")
+	if err := os.MkdirAll(projectPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(projectPath, "TODO.md"), []byte("mine"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := NewProjectCreator(cfg, defaultGenerators()).CreateProject(TicketArgs{Ticket: "PROJ-1", Type: "feature"})
+	if err == nil {
+		t.Fatal("Expected CreateProject to refuse to replace an existing project")
+	}
+	content, _ := os.ReadFile(filepath.Join(projectPath, "TODO.md"))
+	if string(content) != "mine" {
+		t.Errorf("Expected the existing TODO.md to be untouched, but got %q", content)
+	}
+}
+
+func TestCreateProjectEmptyFolder(t *testing.T) {
+	cfg := &config.Config{VaultPath: t.TempDir(), ProjectsPath: "projects"}
+	projectsPath := filepath.Join(cfg.VaultPath, cfg.ProjectsPath)
+	projectPath := filepath.Join(projectsPath, "PROJ-1")
+	if err := os.MkdirAll(projectPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Left by an earlier run, the empty folder is still an existing project
+	err := NewProjectCreator(cfg, defaultGenerators()).CreateProject(TicketArgs{Ticket: "PROJ-1", Type: "feature"})
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("Expected an already exists error, but got %v", err)
+	}
+	if files := projectFiles(t, projectPath); len(files) != 0 {
+		t.Errorf("Expected the folder to stay empty, but got %v", files)
+	}
+	if files := projectFiles(t, projectsPath); len(files) != 1 {
+		t.Errorf("Expected no staging folder left behind, but got %v", files)
+	}
+}
+
+func TestEnsureProject(t *testing.T) {
+	cfg := &config.Config{VaultPath: t.TempDir(), ProjectsPath: "projects"}
+	projectPath := filepath.Join(cfg.VaultPath, cfg.ProjectsPath, "PROJ-1")
+	if err := os.MkdirAll(projectPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(projectPath, "TODO.md"), []byte("mine"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	args := TicketArgs{Ticket: "PROJ-1", Type: "feature"}
+	added, err := NewProjectCreator(cfg, defaultGenerators()).EnsureProject(args)
+	if err != nil {
+		t.Fatalf("EnsureProject returned an error: %v", err)
+	}
+
+	expected := []string{"Investigation.md", "PROJ-1.md"}
+	if len(added) != len(expected) || added[0] != expected[0] || added[1] != expected[1] {
+		t.Errorf("Expected %v to be added, but got %v", expected, added)
+	}
+	content, _ := os.ReadFile(filepath.Join(projectPath, "TODO.md"))
+	if string(content) != "mine" {
+		t.Errorf("Expected the existing TODO.md to be untouched, but got %q", content)
+	}
+
+	added, err = NewProjectCreator(cfg, defaultGenerators()).EnsureProject(args)
+	if err != nil {
+		t.Fatalf("EnsureProject returned an error: %v", err)
+	}
+	if len(added) != 0 {
+		t.Errorf("Expected nothing to be added the second time, but got %v", added)
+	}
+}
+
+func TestMigrateEstimateFileRecordsStart(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{VaultPath: tempDir, ProjectsPath: "projects", ArchivesPath: "archives"}
+	projectPath := filepath.Join(tempDir, "projects", "PROJ-1")
+	if err := os.MkdirAll(projectPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(projectNotePath(projectPath), []byte("# PROJ-1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	legacyPath := filepath.Join(projectPath, "Friday, 3 May 2024.md")
+	if err := os.WriteFile(legacyPath, []byte("# PROJ-1 - Estimate: 3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	written := time.Date(2024, time.April, 29, 10, 0, 0, 0, time.Local)
+	if err := os.Chtimes(legacyPath, written, written); err != nil {
+		t.Fatal(err)
+	}
+
+	legacyName, due, found, err := findLegacyEstimateFile(projectPath)
+	if err != nil || !found {
+		t.Fatalf("Expected to find the legacy estimate file, but got %v, %v", found, err)
+	}
+	if err := migrateEstimateFile(projectPath, legacyName, due); err != nil {
+		t.Fatalf("migrateEstimateFile returned an error: %v", err)
+	}
+
+	// The migrated ticket counts towards the estimate report
+	records, err := readEstimateRecords(cfg)
+	if err != nil {
+		t.Fatalf("readEstimateRecords returned an error: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expected the migrated ticket's estimate, but got %+v", records)
+	}
+	record := records[0]
+	if record.Ticket != "PROJ-1" || record.Estimate != 3 || record.Started.Format("2006-01-02") != "2024-04-29" || record.Due.Format("2006-01-02") != "2024-05-03" {
+		t.Errorf("Expected PROJ-1 estimated at 3 days from 29 April to 3 May, but got %+v", record)
+	}
+}
+
+func TestCheckTicketID(t *testing.T) {
+	for _, id := range []string{"done", "migrate"} {
+		if err := checkTicketID(ticketCmd, id); err == nil {
+			t.Errorf("Expected '%s' to be turned away as a ticket id", id)
+		}
+	}
+	if err := checkTicketID(ticketCmd, "PROJ-1"); err != nil {
+		t.Errorf("Expected PROJ-1 to be a fine ticket id, got %v", err)
+	}
+}
+
+func TestEstimateDueSkipsDaysOff(t *testing.T) {
+	christmas := workday.New(time.Date(2024, time.December, 25, 0, 0, 0, 0, time.Local), time.Date(2024, time.December, 26, 0, 0, 0, 0, time.Local))
+	testCases := []struct {
+		name     string
+		now      time.Time
+		estimate int
+		expected string
+	}{
+		{"Friday skips the weekend", time.Date(2024, time.May, 3, 9, 0, 0, 0, time.Local), 1, "due: 2024-05-06\n"},
+		{"Christmas is skipped", time.Date(2024, time.December, 23, 9, 0, 0, 0, time.Local), 3, "due: 2024-12-30\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			projectPath := t.TempDir()
+			ticketArgs := scheduleTicket(TicketArgs{Ticket: "PROJ-1", Estimate: tc.estimate}, christmas, clock.Fixed(tc.now))
+			generator := &EstimateFileGenerator{TemplateInfo{estimateTemplate()}}
+			if err := generator.Generate(ticketArgs, projectPath); err != nil {
+				t.Fatalf("Generate returned an error: %v", err)
+			}
+
+			content, err := os.ReadFile(filepath.Join(projectPath, estimateFileName))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(content), tc.expected) {
+				t.Errorf("Expected the estimate to say %q, got:\n%s", tc.expected, content)
+			}
+		})
+	}
+}
+
+func TestMigrateEstimateFileKeepsExisting(t *testing.T) {
+	projectPath := t.TempDir()
+	writeNotes(t, projectPath, map[string]string{
+		"Friday, 3 May 2024.md": "# PROJ-1 - Estimate: 3\n",
+		estimateFileName:        "---\nestimate: 1\n---\n",
+	})
+
+	if err := migrateEstimateFile(projectPath, "Friday, 3 May 2024.md", time.Date(2024, time.May, 3, 0, 0, 0, 0, time.Local)); err == nil {
+		t.Fatal("Expected an error when Estimate.md already exists")
+	}
+
+	content, err := os.ReadFile(filepath.Join(projectPath, estimateFileName))
+	if err != nil || string(content) != "---\nestimate: 1\n---\n" {
+		t.Errorf("Expected the existing Estimate.md to be left alone, got %q (%v)", content, err)
+	}
+	if _, err := os.Stat(filepath.Join(projectPath, "Friday, 3 May 2024.md")); err != nil {
+		t.Errorf("Expected the legacy estimate file to be kept: %v", err)
+	}
+	// Nothing half-written is left lying around
+	if leftovers, _ := filepath.Glob(filepath.Join(projectPath, ".*")); len(leftovers) > 0 {
+		t.Errorf("Expected no temporary files, got %v", leftovers)
+	}
+}
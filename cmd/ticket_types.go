@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"gnote/config"
+)
+
+// TemplateFileGenerator concrete implementation, for files whose name and content both come from templates
+type TemplateFileGenerator struct {
+	TemplateInfo
+	name     string
+	fileName *template.Template
+}
+
+func (g *TemplateFileGenerator) Name() string { return g.name }
+
+func (g *TemplateFileGenerator) Generate(ticketArgs TicketArgs, projectPath string) error {
+	var fileName bytes.Buffer
+	if err := g.fileName.Execute(&fileName, ticketArgs); err != nil {
+		return err
+	}
+	return writeProjectFile(g.template, ticketArgs, filepath.Join(projectPath, fileName.String()))
+}
+
+func newTemplateFileGenerator(name string, fileName string, tmpl string) (*TemplateFileGenerator, error) {
+	fileNameT, err := template.New(name + "FileName").Parse(fileName)
+	if err != nil {
+		return nil, err
+	}
+	contentT, err := template.New(name).Parse(tmpl)
+	if err != nil {
+		return nil, err
+	}
+	return &TemplateFileGenerator{TemplateInfo{contentT}, name, fileNameT}, nil
+}
+
+// ticketGenerators builds the generators for every file a ticket type uses.
+func ticketGenerators(cfg *config.Config, ticketType string) ([]FileGenerator, error) {
+	var generators []FileGenerator
+	for _, file := range cfg.TicketFiles(ticketType) {
+		if file.Template == "" {
+			generator, err := builtinGenerator(file.Name)
+			if err != nil {
+				return nil, fmt.Errorf("%s tickets: %w", ticketType, err)
+			}
+			generators = append(generators, generator)
+			continue
+		}
+
+		if file.File == "" {
+			return nil, fmt.Errorf("%s tickets: template %s needs a file name", ticketType, file.Template)
+		}
+		tmpl, err := os.ReadFile(expandHome(file.Template))
+		if err != nil {
+			return nil, fmt.Errorf("%s tickets: %w", ticketType, err)
+		}
+		generator, err := newTemplateFileGenerator(file.GeneratorName(), file.File, string(tmpl))
+		if err != nil {
+			return nil, fmt.Errorf("%s tickets: %w", ticketType, err)
+		}
+		generators = append(generators, generator)
+	}
+	return generators, nil
+}
+
+func builtinGenerator(name string) (FileGenerator, error) {
+	switch name {
+	case "todo":
+		return &TodoFileGenerator{TemplateInfo{todoTemplate()}}, nil
+	case "description":
+		return &DescFileGenerator{TemplateInfo{descTemplate()}}, nil
+	case "estimate":
+		return &EstimateFileGenerator{TemplateInfo{estimateTemplate()}}, nil
+	case "investigation":
+		return &InvestigationFileGenerator{TemplateInfo{investigationTemplate()}}, nil
+	case "repro":
+		return newTemplateFileGenerator(name, "Repro.md", reproTemplate)
+	case "root-cause":
+		return newTemplateFileGenerator(name, "Root Cause.md", rootCauseTemplate)
+	case "question":
+		return newTemplateFileGenerator(name, "Question.md", questionTemplate)
+	case "findings":
+		return newTemplateFileGenerator(name, "Findings.md", findingsTemplate)
+	case "timeline":
+		return newTemplateFileGenerator(name, "Timeline.md", timelineTemplate)
+	default:
+		return nil, fmt.Errorf("unknown ticket file %q", name)
+	}
+}
+
+const reproTemplate = `# [[{{.Ticket}}]] - Repro
+
+## Environment
+
+## Steps to reproduce
+
+1.
+
+## Expected behaviour
+
+## Actual behaviour
+
+`
+
+const rootCauseTemplate = `# [[{{.Ticket}}]] - Root Cause
+
+## What went wrong?
+
+## Why did it happen? (5Why)
+
+## How do we stop it happening again?
+
+`
+
+const questionTemplate = `# [[{{.Ticket}}]] - Question
+
+## What do we need to find out?
+
+## Why does it matter?
+
+## What would a good enough answer look like?
+
+`
+
+const findingsTemplate = `# [[{{.Ticket}}]] - Findings
+
+## Answer
+
+## Evidence
+
+## Follow up tickets
+
+`
+
+const timelineTemplate = `# [[{{.Ticket}}]] - Timeline
+
+## Impact
+
+## Timeline
+
+| Time | Event |
+| ---- | ----- |
+| {{.Started.Format "2006-01-02 15:04"}} | Ticket opened |
+
+## Follow up actions
+
+`
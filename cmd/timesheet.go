@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gnote/clock"
+	"gnote/config"
+	"gnote/daynote"
+	"gnote/timesheet"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	timesheetWeek   bool
+	timesheetFormat string
+)
+
+var timesheetCmd = &cobra.Command{
+	Use:   "timesheet [date]",
+	Short: "Add up the time logged on each ticket",
+	Long: `Adds up the time between 'gnote log start' and 'gnote log stop' for each ticket, for today or --week
+for the week, Monday to Sunday. Give a day for another one, e.g. 'gnote timesheet --week last friday'.
+A timer that's still running counts up to now.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			return
+		}
+		now, err := today(cfg, clock.System)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		date, err := parseDayExpression(strings.Join(args, " "), now)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		from, days := date, 1
+		if timesheetWeek {
+			from, days = weekStart(date), 7
+		}
+		sheet, err := readTimesheet(cfg, clock.System, from, days)
+		if err != nil {
+			fmt.Println("Error reading the log:", err)
+			return
+		}
+
+		switch timesheetFormat {
+		case "table":
+			writeTimesheetTable(os.Stdout, sheet)
+		case "csv":
+			err = timesheet.WriteCSV(os.Stdout, sheet.ByTicket("2006-01-02"))
+		default:
+			err = fmt.Errorf("unknown format %q, use table or csv", timesheetFormat)
+		}
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(timesheetCmd)
+	timesheetCmd.Flags().BoolVarP(&timesheetWeek, "week", "w", false, "Cover the whole week")
+	timesheetCmd.Flags().StringVarP(&timesheetFormat, "format", "o", "table", "Output as table or csv")
+}
+
+// readTimesheet adds up the time logged in the day notes over days days from from.
+func readTimesheet(cfg *config.Config, c clock.Clock, from time.Time, days int) (*timesheet.Sheet, error) {
+	spans, err := readTimeSpans(cfg, c, from, from.AddDate(0, 0, days))
+	if err != nil {
+		return nil, err
+	}
+	day, err := dayBoundary(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return timesheet.NewSheet(spans, from, days, day), nil
+}
+
+// readTimeSpans reads the time logged in the day notes from from up to to. The notes either side are
+// read as well, for timers started the day before or stopped after.
+func readTimeSpans(cfg *config.Config, c clock.Clock, from, to time.Time) ([]timesheet.Span, error) {
+	day, err := dayBoundary(cfg)
+	if err != nil {
+		return nil, err
+	}
+	index, err := loadDayNotes(cfg, from.Location())
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []timesheet.Entry
+	notes := append([]daynote.Note{}, index.Between(from.AddDate(0, 0, -1), to)...)
+	if next, ok := index.Next(to); ok {
+		notes = append(notes, next)
+	}
+	for _, note := range notes {
+		content, err := os.ReadFile(note.Path)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, timesheet.Parse(content, note.Date, day)...)
+	}
+
+	// Only the timer that's still running counts up to now; other unfinished starts were never stopped
+	var until time.Time
+	if running, ok, err := timesheet.ReadTimer(timerPath(cfg)); err != nil {
+		return nil, err
+	} else if ok && !running.Started.After(c.Now()) {
+		until = c.Now()
+	}
+	return timesheet.Spans(entries, until), nil
+}
+
+func writeTimesheetTable(out io.Writer, sheet *timesheet.Sheet) {
+	table := sheet.ByTicket("Mon 2")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.ToUpper(strings.Join(table.Header, "\t")))
+	for _, row := range table.Rows {
+		cells := make([]string, len(row))
+		for i, value := range row {
+			cells[i] = timesheet.Cell(value)
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	w.Flush()
+}
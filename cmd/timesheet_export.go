@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gnote/clock"
+	"gnote/config"
+	"gnote/ics"
+	"gnote/timesheet"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat string
+	exportFrom   string
+	exportTo     string
+	exportPivot  string
+	exportRound  string
+	exportOutput string
+	exportSave   bool
+)
+
+var timesheetExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the logged time for a timesheet system or to share",
+	Long: `Exports the time logged with 'gnote log' as csv, json, html or ical, for this week unless --from and --to
+are given. Each ticket's time on each day is rounded to the nearest 15 minutes, or timesheet.rounding.
+
+csv and json have a row for each ticket on each day, with the columns in timesheet.columns, unless --pivot
+ticket or --pivot day asks for a grid. html is a grid by ticket to share; ical has an event for each stretch
+of work. --save writes the file to the timesheet folder, where Friday's checklist item links to it.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			return
+		}
+		now, err := today(cfg, clock.System)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		from, to := weekStart(now), weekStart(now).AddDate(0, 0, 6)
+		if exportFrom != "" {
+			if from, err = parseDayExpression(exportFrom, now); err != nil {
+				fmt.Println(err)
+				return
+			}
+		}
+		if exportTo != "" {
+			if to, err = parseDayExpression(exportTo, now); err != nil {
+				fmt.Println(err)
+				return
+			}
+		}
+		if to.Before(from) {
+			fmt.Println("--to is before --from")
+			return
+		}
+
+		format := exportFormat
+		if format == "" {
+			format = cfg.Timesheet.Format
+		}
+		if format == "" {
+			format = "csv"
+		}
+		rounding := cfg.Timesheet.Rounding
+		if cmd.Flags().Changed("round") {
+			rounding = exportRound
+		}
+		unit, err := timesheetRounding(rounding)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		var out bytes.Buffer
+		if err := exportTimesheet(&out, cfg, clock.System, from, to, format, exportPivot, unit); err != nil {
+			fmt.Println("Error exporting the timesheet:", err)
+			return
+		}
+
+		output := exportOutput
+		if exportSave {
+			output = filepath.Join(cfg.VaultPath, cfg.TimesheetSubpath(), timesheetFileName(from, to, format))
+			if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+				fmt.Println("Error creating the timesheet folder:", err)
+				return
+			}
+		}
+		if output == "" || output == "-" {
+			os.Stdout.Write(out.Bytes())
+			return
+		}
+		if err := os.WriteFile(output, out.Bytes(), 0644); err != nil {
+			fmt.Println("Error writing the timesheet:", err)
+			return
+		}
+		fmt.Printf("Timesheet written to %s\n", output)
+	},
+}
+
+func init() {
+	timesheetCmd.AddCommand(timesheetExportCmd)
+	timesheetExportCmd.Flags().StringVarP(&exportFormat, "format", "o", "", "csv, json, html or ical; timesheet.format or csv when not given")
+	timesheetExportCmd.Flags().StringVar(&exportFrom, "from", "", "First day to export; Monday this week when not given")
+	timesheetExportCmd.Flags().StringVar(&exportTo, "to", "", "Last day to export; Sunday this week when not given")
+	timesheetExportCmd.Flags().StringVar(&exportPivot, "pivot", "", "Grid of hours by ticket or day, instead of a row for each ticket on each day")
+	timesheetExportCmd.Flags().StringVar(&exportRound, "round", "15m", "Round each ticket's time on each day to this, or 0 for exact")
+	timesheetExportCmd.Flags().StringVarP(&exportOutput, "output", "f", "", "Write to this file instead of stdout")
+	timesheetExportCmd.Flags().BoolVar(&exportSave, "save", false, "Write to the timesheet folder in the vault")
+}
+
+// timesheetRounding reads a rounding like 15m, with 15 minutes when empty and none for 0.
+func timesheetRounding(rounding string) (time.Duration, error) {
+	switch rounding {
+	case "":
+		return 15 * time.Minute, nil
+	case "0", "none":
+		return 0, nil
+	}
+	unit, err := time.ParseDuration(rounding)
+	if err != nil || unit < 0 {
+		return 0, fmt.Errorf("timesheet rounding %q should be a duration like 15m", rounding)
+	}
+	return unit, nil
+}
+
+// timesheetFileName names an export after its week, like "2024-W10 Timesheet.csv", or its days.
+func timesheetFileName(from, to time.Time, format string) string {
+	ext := format
+	if format == "ical" {
+		ext = "ics"
+	}
+	if from.Weekday() == time.Monday && to.Equal(from.AddDate(0, 0, 6)) {
+		year, week := from.ISOWeek()
+		return fmt.Sprintf("%d-W%02d Timesheet.%s", year, week, ext)
+	}
+	return fmt.Sprintf("%s to %s Timesheet.%s", from.Format("2006-01-02"), to.Format("2006-01-02"), ext)
+}
+
+// exportTimesheet writes the time logged from the day of from to the day of to in the format.
+func exportTimesheet(out io.Writer, cfg *config.Config, c clock.Clock, from, to time.Time, format, pivot string, unit time.Duration) error {
+	end := to.AddDate(0, 0, 1)
+	spans, err := readTimeSpans(cfg, c, from, end)
+	if err != nil {
+		return err
+	}
+	if format == "ical" {
+		return ics.Write(out, "-//gnote//timesheet//EN", timesheet.Events(timesheet.Clip(spans, from, end), unit))
+	}
+
+	day, err := dayBoundary(cfg)
+	if err != nil {
+		return err
+	}
+	days := int(end.Sub(from).Hours()/24 + 0.5)
+	sheet := timesheet.NewSheet(spans, from, days, day).Rounded(unit)
+
+	if pivot == "" && format == "html" {
+		pivot = "ticket"
+	}
+	var table timesheet.Table
+	switch pivot {
+	case "", "none":
+		columns := timesheet.DefaultColumns
+		if len(cfg.Timesheet.Columns) > 0 {
+			columns = nil
+			for _, column := range cfg.Timesheet.Columns {
+				columns = append(columns, timesheet.Column{Field: column.Field, Name: column.Name, Format: column.Format})
+			}
+		}
+		if table, err = sheet.Entries(columns); err != nil {
+			return err
+		}
+	case "ticket":
+		table = sheet.ByTicket("2006-01-02")
+	case "day":
+		table = sheet.ByDay("2006-01-02")
+	default:
+		return fmt.Errorf("unknown pivot %q, use ticket, day or none", pivot)
+	}
+
+	switch format {
+	case "csv":
+		return timesheet.WriteCSV(out, table)
+	case "json":
+		return timesheet.WriteJSON(out, table)
+	case "html":
+		title := fmt.Sprintf("Timesheet, %s to %s", from.Format("2 January"), to.Format("2 January 2006"))
+		return timesheet.WriteHTML(out, title, table, sheet.Total())
+	}
+	return fmt.Errorf("unknown format %q, use csv, json, html or ical", format)
+}
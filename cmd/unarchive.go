@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"gnote/config"
+
+	"github.com/spf13/cobra"
+)
+
+// unarchiveCmd represents the unarchive command
+var unarchiveCmd = &cobra.Command{
+	Use:   "unarchive",
+	Short: "Bring archived projects back",
+	Long:  `Moves project folders from the archive's quarter folders back to the projects directory.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			return
+		}
+
+		projectsPath := filepath.Join(cfg.VaultPath, cfg.ProjectsPath)
+		archivePath := filepath.Join(cfg.VaultPath, cfg.ArchivesPath)
+
+		archived, err := listArchivedProjects(archivePath)
+		if err != nil {
+			fmt.Println("Error listing archived projects:", err)
+			return
+		}
+		if len(archived) == 0 {
+			fmt.Println("No archived projects found.")
+			return
+		}
+
+		items := make([]PickerItem, len(archived))
+		for i, path := range archived {
+			items[i] = PickerItem{
+				Label:   fmt.Sprintf("%s (%s)", filepath.Base(path), filepath.Base(filepath.Dir(path))),
+				Keys:    []string{filepath.Base(path)},
+				Preview: projectNotePath(path),
+				Value:   path,
+			}
+		}
+		selected, err := Picker{
+			Title: "Select projects to unarchive:",
+			Items: items,
+			Multi: true,
+		}.Run()
+		if err != nil {
+			fmt.Println("Error during selection:", err)
+			return
+		}
+
+		for _, item := range selected {
+			name := filepath.Base(item.Value)
+			if err := unarchiveProject(projectsPath, item.Value); err != nil {
+				fmt.Printf("Error moving project '%s': %v\n", name, err)
+				continue
+			}
+			fmt.Printf("Project '%s' moved back to '%s'\n", name, projectsPath)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(unarchiveCmd)
+}
+
+// unarchiveProject moves an archived project back into the projects folder, unless a project of that name is already there.
+func unarchiveProject(projectsPath string, archivedPath string) error {
+	destPath := filepath.Join(projectsPath, filepath.Base(archivedPath))
+	exists := fmt.Errorf("'%s' already exists", destPath)
+	if _, err := os.Lstat(destPath); err == nil {
+		return exists
+	}
+	// A project that turned up since the check makes the rename fail instead
+	if err := os.Rename(archivedPath, destPath); err != nil {
+		if errors.Is(err, fs.ErrExist) || errors.Is(err, syscall.ENOTEMPTY) {
+			return exists
+		}
+		return err
+	}
+	return nil
+}
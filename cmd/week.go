@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"gnote/clock"
+	"gnote/config"
+	"gnote/daynote"
+	"gnote/rollup"
+	"gnote/tasks"
+
+	"github.com/spf13/cobra"
+)
+
+type WeekArgs struct {
+	Year  int
+	Week  int
+	Dates string
+	Days  []WeekDay
+	// Tickets are the tickets mentioned in the week's notes, alphabetically
+	Tickets []WeekTicket
+}
+
+// WeekDay is what one day note had ticked off and left open.
+type WeekDay struct {
+	Title string
+	Note  string
+	Done  []string
+	Open  []string
+}
+
+type WeekTicket struct {
+	Ticket string
+	Notes  []string
+}
+
+var weekCmd = &cobra.Command{
+	Use:   "week [date]",
+	Short: "Create a weekly review from the week's day notes.",
+	Long: `Opens the review for this week, or the week of the given day, as YYYY-Www.md.
+It lists what each day note ticked off and left open and the tickets they mention, linking back to the day notes,
+with a reflection section to fill in. Running it again refreshes the lists and keeps your reflection.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			fmt.Println("Error reading config:", err)
+			return
+		}
+		now, err := today(cfg, clock.System)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		date, err := parseDayExpression(strings.Join(args, " "), now)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		index, err := loadDayNotes(cfg, date.Location())
+		if err != nil {
+			fmt.Println("Error reading day notes:", err)
+			return
+		}
+		items, err := checklistItems(cfg)
+		if err != nil {
+			fmt.Println("Error reading the checklist:", err)
+			return
+		}
+		weekArgs, err := buildWeekArgs(index, date, reviewSkippedSections(items))
+		if err != nil {
+			fmt.Println("Error reading day notes:", err)
+			return
+		}
+
+		filePath, err := writeWeekFile(weekFilePath(cfg, date), weekArgs)
+		if err != nil {
+			fmt.Println("Error writing the weekly review:", err)
+			return
+		}
+		openDayFile(cfg, filePath)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(weekCmd)
+}
+
+// weekFilePath is where the review for date's ISO week lives, like 2024-W10.md in week_subpath.
+func weekFilePath(cfg *config.Config, date time.Time) string {
+	year, week := date.ISOWeek()
+	return filepath.Join(cfg.VaultPath, cfg.WeekSubpath(), fmt.Sprintf("%04d-W%02d.md", year, week))
+}
+
+// weekStart is the Monday of date's ISO week.
+func weekStart(date time.Time) time.Time {
+	monday := date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7))
+	return time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, date.Location())
+}
+
+// buildWeekArgs gathers the week's day notes, leaving out the tasks in the skipped sections, like the checklist.
+func buildWeekArgs(index *daynote.Index, date time.Time, skipped map[string]bool) (WeekArgs, error) {
+	monday := weekStart(date)
+	sunday := monday.AddDate(0, 0, 6)
+	year, week := date.ISOWeek()
+	weekArgs := WeekArgs{
+		Year:  year,
+		Week:  week,
+		Dates: fmt.Sprintf("%s to %s", monday.Format("Monday 2 January"), sunday.Format("Monday 2 January 2006")),
+	}
+
+	ticketNotes := map[string][]string{}
+	for _, note := range index.Between(monday, sunday) {
+		content, err := os.ReadFile(note.Path)
+		if err != nil {
+			return WeekArgs{}, err
+		}
+
+		dayTasks, err := rollup.Tasks([]daynote.Note{note})
+		if err != nil {
+			return WeekArgs{}, err
+		}
+		day := WeekDay{Title: note.Date.Format("Monday 2 January"), Note: note.Name()}
+		for _, task := range dayTasks {
+			if skipped[task.Section] {
+				continue
+			}
+			switch task.Status {
+			case tasks.Done:
+				day.Done = append(day.Done, task.Text)
+			case tasks.Open:
+				day.Open = append(day.Open, task.Text)
+			}
+		}
+		weekArgs.Days = append(weekArgs.Days, day)
+
+		seen := map[string]bool{}
+		for _, ticket := range ticketKeyPattern.FindAllString(string(content), -1) {
+			if !seen[ticket] {
+				seen[ticket] = true
+				ticketNotes[ticket] = append(ticketNotes[ticket], note.Name())
+			}
+		}
+	}
+
+	for ticket, notes := range ticketNotes {
+		weekArgs.Tickets = append(weekArgs.Tickets, WeekTicket{Ticket: ticket, Notes: notes})
+	}
+	sort.Slice(weekArgs.Tickets, func(i, j int) bool {
+		return weekArgs.Tickets[i].Ticket < weekArgs.Tickets[j].Ticket
+	})
+	return weekArgs, nil
+}
+
+// writeWeekFile creates the weekly review, or refreshes the Days and Tickets sections of an existing one.
+func writeWeekFile(filePath string, args WeekArgs) (string, error) {
+	return filePath, writeGeneratedNote(filePath, newWeekTemplate(), args, []generatedSection{
+		{"## Days", "days"},
+		{"## Tickets", "tickets"},
+	})
+}
+
+func newWeekTemplate() *template.Template {
+	const weekTemplate = `# Week {{ .Week }}, {{ .Year }}
+
+{{ .Dates }}
+
+## Days
+
+{{ template "days" . }}
+
+## Tickets
+
+{{ template "tickets" . }}
+
+## Reflection
+
+### What went well?
+
+-
+
+### What could have gone better?
+
+-
+
+### What will I focus on next week?
+
+-
+`
+
+	const daysTemplate = `
+{{- range $i, $day := .Days }}
+{{- if $i }}
+
+{{ end }}
+{{- "" }}### [[{{ $day.Note }}|{{ $day.Title }}]]
+{{ range $day.Done }}
+- [x] {{ . }}
+{{- end }}
+{{- range $day.Open }}
+- [ ] {{ . }}
+{{- end }}
+{{- if not (or $day.Done $day.Open) }}
+Nothing ticked off or left open.
+{{- end }}
+{{- else -}}
+No day notes this week.
+{{- end }}`
+
+	const ticketsTemplate = `
+{{- range $i, $ticket := .Tickets }}
+{{- if $i }}
+{{ end }}
+{{- "" }}- [[{{ $ticket.Ticket }}]]: {{ range $j, $note := $ticket.Notes }}{{ if $j }}, {{ end }}[[{{ $note }}]]{{ end }}
+{{- else -}}
+No tickets mentioned.
+{{- end }}`
+
+	t := template.Must(template.New("week").Parse(weekTemplate))
+	template.Must(t.New("days").Parse(daysTemplate))
+	template.Must(t.New("tickets").Parse(ticketsTemplate))
+	return t
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gnote/checklist"
+	"gnote/daynote"
+)
+
+func TestWeekReview(t *testing.T) {
+	root := t.TempDir()
+	notes := map[string]string{
+		"3-4-2024.md":  "# Monday\n\n## Morning Checklist\n\n- [x] check email\n- [ ] check Slack\n\n## Today\n\n- [x] Fix PROJ-12 login\n- [ ] write up the outage\n- [ ]\n",
+		"3-6-2024.md":  "# Wednesday\n\n- [ ] review [[PROJ-12]] and ABC-3\n",
+		"3-11-2024.md": "# Next week\n\n- [x] XYZ-1\n",
+	}
+	var dayNotes []daynote.Note
+	for name, content := range notes {
+		path := filepath.Join(root, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		date, err := time.ParseInLocation("1-2-2006.md", name, time.UTC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dayNotes = append(dayNotes, daynote.Note{Date: date, Path: path})
+	}
+
+	// A Thursday, so the week runs from Monday 4 March
+	args, err := buildWeekArgs(daynote.New(dayNotes), time.Date(2024, time.March, 7, 9, 0, 0, 0, time.UTC), reviewSkippedSections(checklist.Default))
+	if err != nil {
+		t.Fatalf("buildWeekArgs returned an error: %v", err)
+	}
+	weekPath := filepath.Join(root, "Weeks", "2024-W10.md")
+	if _, err := writeWeekFile(weekPath, args); err != nil {
+		t.Fatalf("writeWeekFile returned an error: %v", err)
+	}
+
+	content, err := os.ReadFile(weekPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `# Week 10, 2024
+
+Monday 4 March to Sunday 10 March 2024
+
+## Days
+
+### [[3-4-2024|Monday 4 March]]
+
+- [x] Fix PROJ-12 login
+- [ ] write up the outage
+
+### [[3-6-2024|Wednesday 6 March]]
+
+- [ ] review [[PROJ-12]] and ABC-3
+
+## Tickets
+
+- [[ABC-3]]: [[3-6-2024]]
+- [[PROJ-12]]: [[3-4-2024]], [[3-6-2024]]
+
+## Reflection
+`
+	if !strings.HasPrefix(string(content), expected) {
+		t.Fatalf("Expected the review to start with:\n%s\nbut got:\n%s", expected, content)
+	}
+
+	// Refreshing picks up new notes and keeps what was written in the reflection
+	reflected := strings.Replace(string(content), "### What went well?\n", "### What went well?\n\nShipped the login fix\n", 1)
+	if err := os.WriteFile(weekPath, []byte(reflected), 0644); err != nil {
+		t.Fatal(err)
+	}
+	friday := filepath.Join(root, "3-8-2024.md")
+	if err := os.WriteFile(friday, []byte("## Morning Checklist\n\n- [x] time sheet\n\n## Today\n\n- [x] send the report\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dayNotes = append(dayNotes, daynote.Note{Date: time.Date(2024, time.March, 8, 0, 0, 0, 0, time.UTC), Path: friday})
+	args, err = buildWeekArgs(daynote.New(dayNotes), time.Date(2024, time.March, 7, 9, 0, 0, 0, time.UTC), reviewSkippedSections(checklist.Default))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writeWeekFile(weekPath, args); err != nil {
+		t.Fatalf("writeWeekFile returned an error: %v", err)
+	}
+	content, err = os.ReadFile(weekPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"### [[3-8-2024|Friday 8 March]]\n\n- [x] send the report\n\n## Tickets", "Shipped the login fix"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected the refreshed review to contain %q, but got:\n%s", want, content)
+		}
+	}
+}
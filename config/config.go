@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"os"
 	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -14,6 +17,192 @@ type Config struct {
 	ProjectsPath string `yaml:"projects_subpath"`
 	AreasPath    string `yaml:"areas_subpath"`
 	ArchivesPath string `yaml:"archives_subpath"`
+	// WeekPath is where weekly reviews go; a Weeks folder in the day folder when empty.
+	WeekPath string `yaml:"week_subpath"`
+	// DayFilenameFormat and DayFolderFormat lay out day notes, as Go time layouts or strftime formats
+	// with %q for the quarter. They default to "%-m-%-d-%Y" in period folders.
+	DayFilenameFormat string `yaml:"day_filename_format"`
+	DayFolderFormat   string `yaml:"day_folder_format"`
+	// FiscalYearStartMonth is the month quarters and halves are counted from, 1 to 12; January when unset.
+	FiscalYearStartMonth int `yaml:"fiscal_year_start_month"`
+	// PeriodFolders is how day notes and archives are filed: quarter (the default), month, week or half.
+	PeriodFolders string `yaml:"period_folders"`
+	// DayStartsAt is when one day's note gives way to the next, like "04:00" for late nights; midnight when empty.
+	DayStartsAt string `yaml:"day_starts_at"`
+	// Timezone is the IANA timezone days are reckoned in, like "Europe/London"; the system's when empty.
+	Timezone string `yaml:"timezone"`
+	// CaptureSection is the heading `gnote add` appends to, like "Inbox" or "## Captured"; "## Inbox" when empty.
+	CaptureSection string `yaml:"capture_section"`
+	// Editor is the command notes are opened with; nvim when empty.
+	Editor string `yaml:"editor"`
+	// Repositories are local git checkouts scanned by `gnote day --log-commits`.
+	Repositories []string `yaml:"repositories"`
+	// GitAuthor filters commits by author; defaults to each repo's user.email.
+	GitAuthor string `yaml:"git_author"`
+	// Calendars are ICS files, or folders of them like a CalDAV sync, whose meetings go in each day note.
+	Calendars []string `yaml:"calendars"`
+	// HolidaysFile is an ICS or YAML file of days that don't count towards estimates.
+	HolidaysFile    string           `yaml:"holidays_file"`
+	EstimateOptions []EstimateOption `yaml:"estimate_options"`
+	// Checklist is the recurring items in new day notes; the morning checklist gnote has always had when empty.
+	Checklist []ChecklistItem `yaml:"checklist"`
+	// Timesheet is how `gnote timesheet export` writes timesheets.
+	Timesheet TimesheetConfig `yaml:"timesheet"`
+	// TicketTypes lists the files created for each kind of ticket, e.g. bug, feature or spike.
+	TicketTypes map[string]TicketType `yaml:"ticket_types"`
+}
+
+type TicketType struct {
+	Files []TicketFile `yaml:"files"`
+}
+
+// TicketFile is either one of the built-in files, named on its own (todo, description, estimate,
+// investigation, repro, root-cause, question, findings, timeline), or a file rendered from your own template.
+type TicketFile struct {
+	Name string `yaml:"name"`
+	// File is the file name, which can use the same fields as the template, e.g. "{{.Ticket}} Repro.md"
+	File     string `yaml:"file"`
+	Template string `yaml:"template"`
+}
+
+// UnmarshalYAML lets built-in files be listed by name alone.
+func (f *TicketFile) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		f.Name = node.Value
+		return nil
+	}
+	type plain TicketFile
+	return node.Decode((*plain)(f))
+}
+
+// GeneratorName is the name the file's generator goes by, which for your own templates
+// defaults to the file name.
+func (f TicketFile) GeneratorName() string {
+	if f.Name != "" {
+		return f.Name
+	}
+	return f.File
+}
+
+func builtinFiles(names ...string) TicketType {
+	files := make([]TicketFile, len(names))
+	for i, name := range names {
+		files[i] = TicketFile{Name: name}
+	}
+	return TicketType{Files: files}
+}
+
+var defaultTicketTypes = map[string]TicketType{
+	"feature":  builtinFiles("todo", "description", "estimate", "investigation"),
+	"bug":      builtinFiles("todo", "description", "estimate", "repro", "root-cause"),
+	"spike":    builtinFiles("description", "estimate", "question", "findings"),
+	"incident": builtinFiles("todo", "description", "timeline", "root-cause"),
+}
+
+// TicketFiles returns the files for a ticket type, preferring the config over the defaults.
+// Types that are neither configured nor built in get the feature files.
+func (c *Config) TicketFiles(ticketType string) []TicketFile {
+	if t, ok := c.TicketTypes[ticketType]; ok {
+		return t.Files
+	}
+	if t, ok := defaultTicketTypes[ticketType]; ok {
+		return t.Files
+	}
+	return defaultTicketTypes["feature"].Files
+}
+
+// TicketTypeNames lists the built-in and configured ticket types.
+func (c *Config) TicketTypeNames() []string {
+	var names []string
+	for name := range defaultTicketTypes {
+		names = append(names, name)
+	}
+	for name := range c.TicketTypes {
+		if _, ok := defaultTicketTypes[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WeekSubpath is the folder weekly reviews go in, within the vault.
+func (c *Config) WeekSubpath() string {
+	if c.WeekPath != "" {
+		return c.WeekPath
+	}
+	return filepath.Join(c.DayPath, "Weeks")
+}
+
+// CaptureHeading is the markdown heading captures go under, as a level two heading when no level is given.
+func (c *Config) CaptureHeading() string {
+	section := strings.TrimSpace(c.CaptureSection)
+	if section == "" {
+		return "## Inbox"
+	}
+	if !strings.HasPrefix(section, "#") {
+		return "## " + section
+	}
+	return section
+}
+
+type TimesheetConfig struct {
+	// Format is the export format when --format isn't given: csv, json, html or ical. When it's set,
+	// Friday's time sheet checklist item links to the week's export.
+	Format string `yaml:"format"`
+	// Rounding is what each ticket's time on each day is rounded to, like 15m or 6m; 15m when empty, 0 for none.
+	Rounding string `yaml:"rounding"`
+	// Columns are the columns of csv and json exports, in order; date, ticket and hours when empty.
+	Columns []TimesheetColumn `yaml:"columns"`
+	// Path is where exports are saved; a Timesheets folder in the day folder when empty.
+	Path string `yaml:"subpath"`
+}
+
+// TimesheetColumn names a field of a timesheet export as the timesheet system expects it.
+type TimesheetColumn struct {
+	// Field is date, weekday, ticket, hours or minutes
+	Field string `yaml:"field"`
+	Name  string `yaml:"name"`
+	// Format is the Go time layout for a date, like 02/01/2006
+	Format string `yaml:"format"`
+}
+
+func (c *Config) TimesheetSubpath() string {
+	if c.Timesheet.Path != "" {
+		return c.Timesheet.Path
+	}
+	return filepath.Join(c.DayPath, "Timesheets")
+}
+
+// ChecklistItem is a recurring item in day notes.
+type ChecklistItem struct {
+	// ID names the item; timesheet is the item Friday's timesheet export is linked from
+	ID    string `yaml:"id"`
+	Label string `yaml:"label"`
+	// Schedule is when the item is due, like friday, "mon, wed", last week or day 1; every day when empty
+	Schedule string `yaml:"schedule"`
+	// Section is the heading the item goes under; Morning Checklist when empty
+	Section string `yaml:"section"`
+}
+
+// EstimateOption is one answer to "How much work will this take?", in working days.
+type EstimateOption struct {
+	Label string `yaml:"label"`
+	Days  int    `yaml:"days"`
+}
+
+var defaultEstimateOptions = []EstimateOption{
+	{Label: "None", Days: 0},
+	{Label: "A little", Days: 1},
+	{Label: "A lot", Days: 3},
+}
+
+// Estimates returns the configured estimate options, or the defaults when none are configured.
+func (c *Config) Estimates() []EstimateOption {
+	if len(c.EstimateOptions) == 0 {
+		return defaultEstimateOptions
+	}
+	return c.EstimateOptions
 }
 
 var ReadConfigMock func() (*Config, error)
@@ -0,0 +1,134 @@
+// Package daynote indexes the daily notes in the vault by the date in their file names,
+// so they can be walked in calendar order rather than the order their names sort in.
+package daynote
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Note struct {
+	Date time.Time
+	Path string
+}
+
+// Name is the note's file name without the extension, which is what Obsidian links to.
+func (n Note) Name() string {
+	return strings.TrimSuffix(filepath.Base(n.Path), filepath.Ext(n.Path))
+}
+
+type Index struct {
+	notes []Note
+}
+
+// Load finds every day note under root laid out as layout. Files that aren't laid out like day notes
+// are ignored, and a root that doesn't exist yet is an empty index.
+func Load(root string, layout Layout, loc *time.Location) (*Index, error) {
+	var notes []Note
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		date, ok := layout.Date(rel, loc)
+		if !ok {
+			return nil
+		}
+		notes = append(notes, Note{Date: date, Path: path})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return New(notes), nil
+}
+
+// New builds an index from notes in any order.
+func New(notes []Note) *Index {
+	sorted := append([]Note{}, notes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+	return &Index{notes: sorted}
+}
+
+// Notes returns every note, oldest first.
+func (i *Index) Notes() []Note {
+	return i.notes
+}
+
+// Find returns the note for the day of date.
+func (i *Index) Find(date time.Time) (Note, bool) {
+	n := i.search(date)
+	if n < len(i.notes) && sameDay(i.notes[n].Date, date) {
+		return i.notes[n], true
+	}
+	return Note{}, false
+}
+
+// Prev returns the latest note from a day before date.
+func (i *Index) Prev(date time.Time) (Note, bool) {
+	n := i.search(date)
+	if n == 0 {
+		return Note{}, false
+	}
+	return i.notes[n-1], true
+}
+
+// Next returns the earliest note from a day after date.
+func (i *Index) Next(date time.Time) (Note, bool) {
+	n := i.search(date)
+	if n < len(i.notes) && sameDay(i.notes[n].Date, date) {
+		n++
+	}
+	if n >= len(i.notes) {
+		return Note{}, false
+	}
+	return i.notes[n], true
+}
+
+// Between returns the notes from the day of from up to and including the day of to, oldest first.
+// A zero from or to leaves that end open.
+func (i *Index) Between(from time.Time, to time.Time) []Note {
+	start := 0
+	if !from.IsZero() {
+		start = i.search(from)
+	}
+	end := len(i.notes)
+	if !to.IsZero() {
+		end = i.search(startOfDay(to).AddDate(0, 0, 1))
+	}
+	if start >= end {
+		return nil
+	}
+	return i.notes[start:end]
+}
+
+// search returns the position of the first note on or after the day of date.
+func (i *Index) search(date time.Time) int {
+	day := startOfDay(date)
+	return sort.Search(len(i.notes), func(n int) bool {
+		return !startOfDay(i.notes[n].Date).Before(day)
+	})
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func sameDay(a time.Time, b time.Time) bool {
+	return a.Year() == b.Year() && a.Month() == b.Month() && a.Day() == b.Day()
+}
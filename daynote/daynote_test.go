@@ -0,0 +1,110 @@
+package daynote
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeNotes(t *testing.T, root string, paths ...string) {
+	for _, path := range paths {
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte("# day\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 12, 0, 0, 0, time.UTC)
+}
+
+func names(notes []Note) []string {
+	var result []string
+	for _, note := range notes {
+		result = append(result, note.Name())
+	}
+	return result
+}
+
+func TestLoadSortsByDate(t *testing.T) {
+	root := t.TempDir()
+	writeNotes(t, root,
+		"2024_Q4/10-1-2024.md",
+		"2024_Q1/2-1-2024.md",
+		"2024_Q1/2-10-2024.md",
+		"2024_Q1/2-9-2024.md",
+		"2023_Q4/12-29-2023.md",
+		"2024_Q1/notes.md",
+	)
+
+	index, err := Load(root, DefaultLayout(), time.UTC)
+	if err != nil {
+		t.Fatalf("Load returned an error: %v", err)
+	}
+
+	expected := []string{"12-29-2023", "2-1-2024", "2-9-2024", "2-10-2024", "10-1-2024"}
+	got := names(index.Notes())
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %v, but got %v", expected, got)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Fatalf("Expected %v, but got %v", expected, got)
+		}
+	}
+}
+
+func TestLoadMissingRoot(t *testing.T) {
+	index, err := Load(filepath.Join(t.TempDir(), "missing"), DefaultLayout(), time.UTC)
+	if err != nil {
+		t.Fatalf("Load returned an error: %v", err)
+	}
+	if len(index.Notes()) != 0 {
+		t.Errorf("Expected no notes, but got %v", index.Notes())
+	}
+}
+
+func TestNavigation(t *testing.T) {
+	index := New([]Note{
+		{Date: day(2024, time.February, 9), Path: "2-9-2024.md"},
+		{Date: day(2024, time.January, 31), Path: "1-31-2024.md"},
+		{Date: day(2024, time.February, 12), Path: "2-12-2024.md"},
+	})
+
+	testCases := []struct {
+		name     string
+		got      func() (Note, bool)
+		expected string
+	}{
+		{"Prev of a note", func() (Note, bool) { return index.Prev(day(2024, time.February, 9)) }, "1-31-2024"},
+		{"Prev of a day without a note", func() (Note, bool) { return index.Prev(day(2024, time.February, 11)) }, "2-9-2024"},
+		{"Prev of the first note", func() (Note, bool) { return index.Prev(day(2024, time.January, 31)) }, ""},
+		{"Next of a note", func() (Note, bool) { return index.Next(day(2024, time.February, 9)) }, "2-12-2024"},
+		{"Next of a day without a note", func() (Note, bool) { return index.Next(day(2024, time.February, 1)) }, "2-9-2024"},
+		{"Next of the last note", func() (Note, bool) { return index.Next(day(2024, time.February, 12)) }, ""},
+		{"Find", func() (Note, bool) { return index.Find(day(2024, time.February, 12)) }, "2-12-2024"},
+		{"Find missing", func() (Note, bool) { return index.Find(day(2024, time.February, 13)) }, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			note, ok := tc.got()
+			if ok != (tc.expected != "") || note.Name() != tc.expected && ok {
+				t.Errorf("Expected %q, but got %q (found %t)", tc.expected, note.Name(), ok)
+			}
+		})
+	}
+
+	between := names(index.Between(day(2024, time.February, 1), day(2024, time.February, 12)))
+	if len(between) != 2 || between[0] != "2-9-2024" || between[1] != "2-12-2024" {
+		t.Errorf("Expected 2-9-2024 and 2-12-2024, but got %v", between)
+	}
+	if all := index.Between(time.Time{}, time.Time{}); len(all) != 3 {
+		t.Errorf("Expected every note, but got %v", names(all))
+	}
+}
@@ -0,0 +1,360 @@
+package daynote
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gnote/period"
+)
+
+const (
+	// DefaultFileFormat and DefaultFolderFormat are the original layout: 3-5-2024.md in a 2024_Q1 folder.
+	DefaultFileFormat   = "%-m-%-d-%Y"
+	DefaultFolderFormat = "%Y_Q%q"
+	extension           = ".md"
+)
+
+// Layout is where day notes live under the day folder, built from a folder and a file name format.
+// Formats are Go time layouts like "2006/01" and "2006-01-02", or strftime formats like "%Y/%m" and
+// "%Y-%m-%d" when they contain a %. strftime formats also have gnote's own %q for the quarter, %h for the half
+// and %f for the fiscal year, which follow the layout's calendar, and %G and %V for the ISO week.
+type Layout struct {
+	folder   []token
+	file     []token
+	calendar period.Calendar
+	// pattern matches a note's path relative to the day folder, with a group per field token
+	pattern *regexp.Regexp
+	fields  []field
+}
+
+// NewLayout parses the folder and file name formats, using the defaults for empty ones.
+// The file name gets a .md extension, and together the formats must pin down the date.
+// Quarters, halves and fiscal years are counted with calendar.
+func NewLayout(folderFormat string, fileFormat string, calendar period.Calendar) (Layout, error) {
+	if folderFormat == "" {
+		folderFormat = DefaultFolderFormat
+	}
+	if fileFormat == "" {
+		fileFormat = DefaultFileFormat
+	}
+	folder, err := parseFormat(folderFormat)
+	if err != nil {
+		return Layout{}, fmt.Errorf("day_folder_format: %w", err)
+	}
+	file, err := parseFormat(strings.TrimSuffix(fileFormat, extension))
+	if err != nil {
+		return Layout{}, fmt.Errorf("day_filename_format: %w", err)
+	}
+
+	l := Layout{folder: folder, file: append(file, token{literal: extension}), calendar: calendar}
+	tokens := l.file
+	if len(folder) > 0 {
+		tokens = append(append(append([]token{}, folder...), token{literal: "/"}), l.file...)
+	}
+
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	has := map[field]bool{}
+	for _, t := range tokens {
+		if t.field == noField {
+			pattern.WriteString(regexp.QuoteMeta(t.literal))
+			continue
+		}
+		pattern.WriteString("(" + fieldPatterns[t.field] + ")")
+		l.fields = append(l.fields, t.field)
+		has[t.field] = true
+	}
+	pattern.WriteString("$")
+	l.pattern = regexp.MustCompile(pattern.String())
+
+	hasYear := has[year] || has[shortYear]
+	hasMonth := has[month] || has[paddedMonth] || has[monthName] || has[shortMonthName]
+	hasDay := has[dayOfMonth] || has[paddedDayOfMonth]
+	if !hasYear || !(hasMonth && hasDay || has[dayOfYear]) {
+		return Layout{}, fmt.Errorf("%q and %q don't include the year, month and day", folderFormat, fileFormat)
+	}
+	return l, nil
+}
+
+// DefaultLayout is the layout when nothing is configured.
+func DefaultLayout() Layout {
+	l, err := NewLayout("", "", period.Calendar{})
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+// Path is where the note for date lives under root.
+func (l Layout) Path(root string, date time.Time) string {
+	var parts []string
+	if len(l.folder) > 0 {
+		parts = append(parts, l.format(l.folder, date))
+	}
+	return filepath.Join(root, filepath.Join(append(parts, l.format(l.file, date))...))
+}
+
+// Date reads the date back out of a note's path relative to the day folder.
+// It reports false for paths that aren't laid out like day notes.
+func (l Layout) Date(rel string, loc *time.Location) (time.Time, bool) {
+	match := l.pattern.FindStringSubmatch(filepath.ToSlash(rel))
+	if match == nil {
+		return time.Time{}, false
+	}
+
+	values := map[field]int{}
+	for i, f := range l.fields {
+		value, ok := fieldValue(f, match[i+1])
+		if !ok {
+			return time.Time{}, false
+		}
+		// Fields that appear twice, like the year in both folder and file name, have to agree
+		if previous, seen := values[f]; seen && previous != value {
+			return time.Time{}, false
+		}
+		values[f] = value
+	}
+
+	y, ok := values[year]
+	if !ok {
+		y = 2000 + values[shortYear]
+	}
+	var date time.Time
+	if doy, ok := values[dayOfYear]; ok {
+		date = time.Date(y, time.January, doy, 0, 0, 0, 0, loc)
+	} else {
+		m := firstValue(values, month, paddedMonth, monthName, shortMonthName)
+		d := firstValue(values, dayOfMonth, paddedDayOfMonth)
+		date = time.Date(y, time.Month(m), d, 0, 0, 0, 0, loc)
+	}
+
+	// Everything else in the path, like the quarter or weekday, has to match the date too,
+	// which also rules out dates that don't exist such as 2-30-2024
+	if filepath.ToSlash(l.Path("", date)) != filepath.ToSlash(rel) {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+func firstValue(values map[field]int, fields ...field) int {
+	for _, f := range fields {
+		if value, ok := values[f]; ok {
+			return value
+		}
+	}
+	return 0
+}
+
+type field int
+
+const (
+	noField field = iota
+	year
+	shortYear
+	month
+	paddedMonth
+	monthName
+	shortMonthName
+	dayOfMonth
+	paddedDayOfMonth
+	dayOfYear
+	weekdayName
+	shortWeekdayName
+	quarter
+	half
+	fiscalYear
+	isoYear
+	isoWeek
+)
+
+type token struct {
+	field   field
+	literal string
+}
+
+var fieldPatterns = map[field]string{
+	year:             `\d{4}`,
+	shortYear:        `\d{2}`,
+	month:            `\d{1,2}`,
+	paddedMonth:      `\d{2}`,
+	monthName:        `[A-Za-z]+`,
+	shortMonthName:   `[A-Za-z]{3}`,
+	dayOfMonth:       `\d{1,2}`,
+	paddedDayOfMonth: `\d{2}`,
+	dayOfYear:        `\d{3}`,
+	weekdayName:      `[A-Za-z]+`,
+	shortWeekdayName: `[A-Za-z]{3}`,
+	quarter:          `[1-4]`,
+	half:             `[12]`,
+	fiscalYear:       `\d{4}`,
+	isoYear:          `\d{4}`,
+	isoWeek:          `\d{2}`,
+}
+
+// strftimeFields are the strftime conversions a layout understands.
+var strftimeFields = map[string]field{
+	"Y":  year,
+	"y":  shortYear,
+	"m":  paddedMonth,
+	"-m": month,
+	"B":  monthName,
+	"b":  shortMonthName,
+	"d":  paddedDayOfMonth,
+	"-d": dayOfMonth,
+	"j":  dayOfYear,
+	"A":  weekdayName,
+	"a":  shortWeekdayName,
+	"q":  quarter,
+	"h":  half,
+	"f":  fiscalYear,
+	"G":  isoYear,
+	"V":  isoWeek,
+}
+
+// goFields are the Go layout elements a layout understands, longest first so "January" wins over "Jan".
+var goFields = []struct {
+	element string
+	field   field
+}{
+	{"January", monthName},
+	{"Monday", weekdayName},
+	{"2006", year},
+	{"Jan", shortMonthName},
+	{"Mon", shortWeekdayName},
+	{"002", dayOfYear},
+	{"01", paddedMonth},
+	{"02", paddedDayOfMonth},
+	{"06", shortYear},
+	{"1", month},
+	{"2", dayOfMonth},
+}
+
+func parseFormat(layout string) ([]token, error) {
+	if strings.Contains(layout, "%") {
+		return parseStrftime(layout)
+	}
+	return parseGoLayout(layout), nil
+}
+
+func parseStrftime(layout string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(layout); i++ {
+		if layout[i] != '%' {
+			tokens = appendLiteral(tokens, layout[i:i+1])
+			continue
+		}
+		spec := ""
+		if i+1 < len(layout) {
+			spec = layout[i+1 : i+2]
+			if spec == "-" && i+2 < len(layout) {
+				spec = layout[i+1 : i+3]
+			}
+		}
+		if spec == "%" {
+			tokens = appendLiteral(tokens, "%")
+			i++
+			continue
+		}
+		f, ok := strftimeFields[spec]
+		if !ok {
+			return nil, fmt.Errorf("%q has an unknown %%%s", layout, spec)
+		}
+		tokens = append(tokens, token{field: f})
+		i += len(spec)
+	}
+	return tokens, nil
+}
+
+func parseGoLayout(layout string) []token {
+	var tokens []token
+	for len(layout) > 0 {
+		matched := false
+		for _, g := range goFields {
+			if strings.HasPrefix(layout, g.element) {
+				tokens = append(tokens, token{field: g.field})
+				layout = layout[len(g.element):]
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			tokens = appendLiteral(tokens, layout[:1])
+			layout = layout[1:]
+		}
+	}
+	return tokens
+}
+
+func appendLiteral(tokens []token, s string) []token {
+	if n := len(tokens); n > 0 && tokens[n-1].field == noField {
+		tokens[n-1].literal += s
+		return tokens
+	}
+	return append(tokens, token{literal: s})
+}
+
+func (l Layout) format(tokens []token, date time.Time) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		switch t.field {
+		case noField:
+			b.WriteString(t.literal)
+		case year:
+			fmt.Fprintf(&b, "%04d", date.Year())
+		case shortYear:
+			fmt.Fprintf(&b, "%02d", date.Year()%100)
+		case month:
+			fmt.Fprintf(&b, "%d", date.Month())
+		case paddedMonth:
+			fmt.Fprintf(&b, "%02d", date.Month())
+		case monthName:
+			b.WriteString(date.Month().String())
+		case shortMonthName:
+			b.WriteString(date.Month().String()[:3])
+		case dayOfMonth:
+			fmt.Fprintf(&b, "%d", date.Day())
+		case paddedDayOfMonth:
+			fmt.Fprintf(&b, "%02d", date.Day())
+		case dayOfYear:
+			fmt.Fprintf(&b, "%03d", date.YearDay())
+		case weekdayName:
+			b.WriteString(date.Weekday().String())
+		case shortWeekdayName:
+			b.WriteString(date.Weekday().String()[:3])
+		case quarter:
+			fmt.Fprintf(&b, "%d", l.calendar.Quarter(date))
+		case half:
+			fmt.Fprintf(&b, "%d", l.calendar.Half(date))
+		case fiscalYear:
+			fmt.Fprintf(&b, "%04d", l.calendar.FiscalYear(date))
+		case isoYear:
+			year, _ := date.ISOWeek()
+			fmt.Fprintf(&b, "%04d", year)
+		case isoWeek:
+			_, week := date.ISOWeek()
+			fmt.Fprintf(&b, "%02d", week)
+		}
+	}
+	return b.String()
+}
+
+func fieldValue(f field, s string) (int, bool) {
+	switch f {
+	case monthName, shortMonthName:
+		for m := time.January; m <= time.December; m++ {
+			if strings.EqualFold(s, m.String()) || strings.EqualFold(s, m.String()[:3]) {
+				return int(m), true
+			}
+		}
+		return 0, false
+	case weekdayName, shortWeekdayName:
+		// Checked against the date once it's known
+		return 0, true
+	}
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
@@ -0,0 +1,158 @@
+package daynote
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gnote/period"
+)
+
+func TestLayoutPathAndDate(t *testing.T) {
+	date := day(2024, time.March, 5)
+
+	testCases := []struct {
+		name   string
+		folder string
+		file   string
+		path   string
+	}{
+		{"default", "", "", "2024_Q1/3-5-2024.md"},
+		{"strftime", "%Y/%m", "%Y-%m-%d", "2024/03/2024-03-05.md"},
+		{"go layout", "2006/01", "2006-01-02", "2024/03/2024-03-05.md"},
+		{"go layout with extension", "2006", "2006-01-02.md", "2024/2024-03-05.md"},
+		{"names", "%Y/%B", "%a %-d %b %Y", "2024/March/Tue 5 Mar 2024.md"},
+		{"day of year", "%Y_Q%q", "%Y-%j", "2024_Q1/2024-065.md"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			layout, err := NewLayout(tc.folder, tc.file, period.Calendar{})
+			if err != nil {
+				t.Fatalf("NewLayout returned an error: %v", err)
+			}
+			got := filepath.ToSlash(layout.Path("", date))
+			if got != tc.path {
+				t.Errorf("Expected path %q, but got %q", tc.path, got)
+			}
+
+			parsed, ok := layout.Date(tc.path, time.UTC)
+			if !ok {
+				t.Fatalf("Expected %q to be read back as a date", tc.path)
+			}
+			if parsed.Format("2006-01-02") != "2024-03-05" {
+				t.Errorf("Expected 2024-03-05, but got %s", parsed.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestLayoutDateRejectsOtherPaths(t *testing.T) {
+	layout := DefaultLayout()
+	for _, path := range []string{
+		"2024_Q1/notes.md",
+		"2024_Q2/3-5-2024.md",
+		"2024_Q1/2-30-2024.md",
+		"2023_Q1/3-5-2024.md",
+		"3-5-2024.md",
+		"2024_Q1/3-5-2024.txt",
+	} {
+		if _, ok := layout.Date(path, time.UTC); ok {
+			t.Errorf("Expected %q not to be a day note", path)
+		}
+	}
+}
+
+func TestNewLayoutNeedsTheWholeDate(t *testing.T) {
+	for _, formats := range [][2]string{
+		{"%Y", "%m"},
+		{"notes", "%m-%d"},
+		{"%Y", "%x"},
+	} {
+		if _, err := NewLayout(formats[0], formats[1], period.Calendar{}); err == nil {
+			t.Errorf("Expected %q and %q to be rejected", formats[0], formats[1])
+		}
+	}
+}
+
+func TestMigration(t *testing.T) {
+	root := t.TempDir()
+	writeNotes(t, root, "2024_Q1/3-4-2024.md", "2024_Q1/3-5-2024.md", "2024_Q1/notes.md")
+	if err := os.WriteFile(filepath.Join(root, "2024_Q1", "3-5-2024.md"),
+		[]byte("← [[3-4-2024]] | [[2024_Q1/3-6-2024.md|Wednesday]] →\n[[notes]]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	to, err := NewLayout("%Y/%m", "%Y-%m-%d", period.Calendar{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	moves, err := PlanMigration(root, DefaultLayout(), to, time.UTC)
+	if err != nil {
+		t.Fatalf("PlanMigration returned an error: %v", err)
+	}
+	if len(moves) != 2 {
+		t.Fatalf("Expected 2 moves, but got %v", moves)
+	}
+	if err := ApplyMigration(root, moves); err != nil {
+		t.Fatalf("ApplyMigration returned an error: %v", err)
+	}
+
+	index, err := Load(root, to, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(names(index.Notes()), ","); got != "2024-03-04,2024-03-05" {
+		t.Errorf("Expected the notes in the new layout, but got %s", got)
+	}
+	// The old folder still has notes.md in it, so it stays
+	if _, err := os.Stat(filepath.Join(root, "2024_Q1", "notes.md")); err != nil {
+		t.Errorf("Expected notes.md to be left alone: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(root, "2024", "03", "2024-03-05.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	moves = append(moves, Move{From: "2024_Q1/3-6-2024.md", To: "2024/03/2024-03-06.md"})
+	rewritten, changed := RewriteLinks(content, moves)
+	expected := "← [[2024-03-04]] | [[2024-03-06.md|Wednesday]] →\n[[notes]]\n"
+	if !changed || string(rewritten) != expected {
+		t.Errorf("Expected links to be rewritten to %q, but got %q", expected, rewritten)
+	}
+}
+
+func TestPlanMigrationWontOverwrite(t *testing.T) {
+	root := t.TempDir()
+	writeNotes(t, root, "2024_Q1/3-5-2024.md", "2024/03/2024-03-05.md")
+
+	to, err := NewLayout("%Y/%m", "%Y-%m-%d", period.Calendar{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := PlanMigration(root, DefaultLayout(), to, time.UTC); err == nil {
+		t.Error("Expected a migration onto an existing note to fail")
+	}
+}
+
+func TestLayoutPeriodFolders(t *testing.T) {
+	february := period.Calendar{FiscalYearStart: time.February}
+	for bucket, expected := range map[period.Bucket]string{
+		period.Quarterly: "2025_Q1/3-5-2024.md",
+		period.HalfYear:  "2025_H1/3-5-2024.md",
+		period.Weekly:    "2024-W10/3-5-2024.md",
+	} {
+		layout, err := NewLayout(bucket.Format(), "", february)
+		if err != nil {
+			t.Fatalf("NewLayout returned an error: %v", err)
+		}
+		if got := filepath.ToSlash(layout.Path("", day(2024, time.March, 5))); got != expected {
+			t.Errorf("Expected %s folders to give %q, but got %q", bucket, expected, got)
+		}
+		if _, ok := layout.Date(expected, time.UTC); !ok {
+			t.Errorf("Expected %q to be read back as a date", expected)
+		}
+	}
+}
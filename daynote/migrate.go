@@ -0,0 +1,117 @@
+package daynote
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Move is one note's rename from one layout to another.
+type Move struct {
+	Date time.Time
+	From string
+	To   string
+}
+
+// Renamed reports whether the note's name changes, and with it the links to it.
+func (m Move) Renamed() bool {
+	return filepath.Base(m.From) != filepath.Base(m.To)
+}
+
+// PlanMigration works out where every note laid out as from goes when laid out as to.
+// Notes already in place are left out. It fails rather than plan a move onto a file that exists,
+// or two notes onto the same path, so nothing is overwritten.
+func PlanMigration(root string, from Layout, to Layout, loc *time.Location) ([]Move, error) {
+	index, err := Load(root, from, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	var moves []Move
+	targets := map[string]string{}
+	for _, note := range index.Notes() {
+		target := to.Path(root, note.Date)
+		if target == note.Path {
+			continue
+		}
+		if other, ok := targets[target]; ok {
+			return nil, fmt.Errorf("%s and %s would both move to %s", other, note.Path, target)
+		}
+		if _, err := os.Stat(target); err == nil {
+			return nil, fmt.Errorf("can't move %s to %s, it already exists", note.Path, target)
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+		targets[target] = note.Path
+		moves = append(moves, Move{Date: note.Date, From: note.Path, To: target})
+	}
+	return moves, nil
+}
+
+// ApplyMigration moves the notes, then removes the folders they leave empty under root.
+func ApplyMigration(root string, moves []Move) error {
+	for _, move := range moves {
+		if err := os.MkdirAll(filepath.Dir(move.To), 0755); err != nil {
+			return err
+		}
+		// Link rather than rename so a note that appeared at the target since planning isn't replaced
+		if err := os.Link(move.From, move.To); err != nil {
+			return fmt.Errorf("moving %s: %w", move.From, err)
+		}
+		if err := os.Remove(move.From); err != nil {
+			return err
+		}
+	}
+	for _, move := range moves {
+		for dir := filepath.Dir(move.From); dir != root && strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+			// Fails, and stops, at the first folder that still has something in it
+			if os.Remove(dir) != nil {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// RewriteLinks points wiki links like [[3-5-2024]], [[3-5-2024|Tuesday]] or [[3-5-2024#Commits]]
+// at the notes' new names. It reports whether anything changed.
+func RewriteLinks(content []byte, moves []Move) ([]byte, bool) {
+	names := map[string]string{}
+	for _, move := range moves {
+		if move.Renamed() {
+			names[noteName(move.From)] = noteName(move.To)
+		}
+	}
+	if len(names) == 0 {
+		return content, false
+	}
+
+	changed := false
+	rewritten := wikiLinkPattern.ReplaceAllFunc(content, func(link []byte) []byte {
+		match := wikiLinkPattern.FindSubmatch(link)
+		target := string(match[1])
+		// Links can include the folder and extension, only the name is looked up
+		name := target[strings.LastIndex(target, "/")+1:]
+		ext := ""
+		if strings.HasSuffix(name, extension) {
+			name, ext = strings.TrimSuffix(name, extension), extension
+		}
+		renamed, ok := names[name]
+		if !ok {
+			return link
+		}
+		changed = true
+		// A path to the old folder would be wrong, so the link becomes the bare name Obsidian resolves
+		return []byte("[[" + renamed + ext + string(match[2]) + "]]")
+	})
+	return rewritten, changed
+}
+
+var wikiLinkPattern = regexp.MustCompile(`\[\[([^\]|#]+)([|#][^\]]*)?\]\]`)
+
+func noteName(path string) string {
+	return Note{Path: path}.Name()
+}
@@ -0,0 +1,26 @@
+// Package filelock keeps two gnote processes from editing the same folder of notes at once,
+// so appends from two shells don't overwrite each other.
+package filelock
+
+import "time"
+
+// wait is how long Acquire keeps trying before giving up on a lock someone else holds.
+const wait = 10 * time.Second
+
+// Lock is held on a folder until Unlock is called.
+type Lock struct {
+	release func() error
+}
+
+// Acquire waits until no other process holds the lock on dir, then takes it.
+func Acquire(dir string) (*Lock, error) {
+	release, err := lock(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Lock{release: release}, nil
+}
+
+func (l *Lock) Unlock() error {
+	return l.release()
+}
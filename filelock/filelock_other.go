@@ -0,0 +1,40 @@
+//go:build !unix
+
+package filelock
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// lockFile is created in the folder while the lock is held.
+const lockFile = ".gnote.lock"
+
+// stale is how old a lock file has to be before it's taken to be left over from a process that died.
+const stale = time.Minute
+
+// lock creates a lock file in the folder, which only one process can do at a time.
+func lock(dir string) (func() error, error) {
+	path := filepath.Join(dir, lockFile)
+	deadline := time.Now().Add(wait)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			return func() error { return os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > stale {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is locked by another gnote", dir)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
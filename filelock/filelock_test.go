@@ -0,0 +1,41 @@
+package filelock
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLockSerializesAppends(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "note.md")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l, err := Acquire(dir)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer l.Unlock()
+			content, _ := os.ReadFile(path)
+			if err := os.WriteFile(path, append(content, "- captured\n"...), 0644); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(content), "- captured\n"); got != 20 {
+		t.Errorf("Expected 20 captures, but got %d", got)
+	}
+}
@@ -0,0 +1,113 @@
+// Package focus writes focus sessions, timed stretches of work on one thing, into the Focus section of a day
+// note, one line each, with the day's total under them.
+package focus
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Heading is the day note section sessions are written under.
+const Heading = "## Focus"
+
+type Session struct {
+	Ticket  string
+	Start   time.Time
+	End     time.Time
+	Planned time.Duration
+	// Interruptions are the times something broke in during the session, without stopping it
+	Interruptions int
+	// Stopped is set when the session ended before its time was up, along with why
+	Stopped bool
+	Reason  string
+}
+
+func (s Session) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// String is the session's line in the Focus section, like
+// "- 09:00–09:25 [[PROJ-1]] 25m, 1 interruption" or "- 10:00–10:12 12m of 25m, 0 interruptions, stopped: a meeting".
+func (s Session) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "- %s–%s ", s.Start.Format("15:04"), s.End.Format("15:04"))
+	if s.Ticket != "" {
+		fmt.Fprintf(&b, "[[%s]] ", s.Ticket)
+	}
+	b.WriteString(FormatDuration(s.Duration()))
+	if s.Stopped {
+		fmt.Fprintf(&b, " of %s", FormatDuration(s.Planned))
+	}
+	if s.Interruptions == 1 {
+		b.WriteString(", 1 interruption")
+	} else {
+		fmt.Fprintf(&b, ", %d interruptions", s.Interruptions)
+	}
+	if s.Stopped {
+		b.WriteString(", stopped")
+		if s.Reason != "" {
+			b.WriteString(": " + s.Reason)
+		}
+	}
+	return b.String()
+}
+
+// FormatDuration writes a duration to the minute, like 25m or 1h05m.
+func FormatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
+
+var (
+	sessionPattern = regexp.MustCompile(`^- (\d{2}):(\d{2})–(\d{2}):(\d{2}) `)
+	totalPattern   = regexp.MustCompile(`^\*\*Total:\*\*`)
+)
+
+// AddSession adds the session's line to the body of a Focus section and brings the total up to date.
+// Lines written by hand are kept where they are.
+func AddSession(body string, session Session) string {
+	var lines []string
+	for _, line := range strings.Split(strings.Trim(body, "\n"), "\n") {
+		if totalPattern.MatchString(line) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	lines = append(lines, session.String())
+
+	var total time.Duration
+	sessions := 0
+	for _, line := range lines {
+		match := sessionPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		var startHour, startMinute, endHour, endMinute int
+		fmt.Sscan(match[1], &startHour)
+		fmt.Sscan(match[2], &startMinute)
+		fmt.Sscan(match[3], &endHour)
+		fmt.Sscan(match[4], &endMinute)
+		minutes := (endHour*60 + endMinute) - (startHour*60 + startMinute)
+		if minutes < 0 {
+			// Ran past midnight
+			minutes += 24 * 60
+		}
+		total += time.Duration(minutes) * time.Minute
+		sessions++
+	}
+
+	noun := "sessions"
+	if sessions == 1 {
+		noun = "session"
+	}
+	lines = append(lines, "", fmt.Sprintf("**Total:** %s in %d %s", FormatDuration(total), sessions, noun))
+	return strings.Join(lines, "\n")
+}
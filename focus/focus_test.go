@@ -0,0 +1,49 @@
+package focus
+
+import (
+	"testing"
+	"time"
+)
+
+func at(hour, minute int) time.Time {
+	return time.Date(2024, time.March, 4, hour, minute, 0, 0, time.UTC)
+}
+
+func TestSessionString(t *testing.T) {
+	tests := []struct {
+		session  Session
+		expected string
+	}{
+		{
+			session:  Session{Ticket: "PROJ-1", Start: at(9, 0), End: at(9, 25), Planned: 25 * time.Minute, Interruptions: 1},
+			expected: "- 09:00–09:25 [[PROJ-1]] 25m, 1 interruption",
+		},
+		{
+			session:  Session{Start: at(10, 0), End: at(10, 12), Planned: 25 * time.Minute, Stopped: true, Reason: "a meeting"},
+			expected: "- 10:00–10:12 12m of 25m, 0 interruptions, stopped: a meeting",
+		},
+		{
+			session:  Session{Start: at(13, 0), End: at(14, 30), Planned: 90 * time.Minute, Interruptions: 2},
+			expected: "- 13:00–14:30 1h30m, 2 interruptions",
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.session.String(); got != tt.expected {
+			t.Errorf("Expected %q, but got %q", tt.expected, got)
+		}
+	}
+}
+
+func TestAddSession(t *testing.T) {
+	body := AddSession("", Session{Ticket: "PROJ-1", Start: at(9, 0), End: at(9, 25), Planned: 25 * time.Minute})
+	if want := "- 09:00–09:25 [[PROJ-1]] 25m, 0 interruptions\n\n**Total:** 25m in 1 session"; body != want {
+		t.Errorf("Expected %q, but got %q", want, body)
+	}
+
+	// A note written by hand stays, and the total is worked out again
+	body = AddSession(body+"\nSlow after lunch\n", Session{Start: at(23, 50), End: at(0, 30).AddDate(0, 0, 1), Planned: 40 * time.Minute})
+	want := "- 09:00–09:25 [[PROJ-1]] 25m, 0 interruptions\n\nSlow after lunch\n- 23:50–00:30 40m, 0 interruptions\n\n**Total:** 1h05m in 2 sessions"
+	if body != want {
+		t.Errorf("Expected %q, but got %q", want, body)
+	}
+}
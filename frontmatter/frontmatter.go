@@ -0,0 +1,129 @@
+// Package frontmatter reads and edits the YAML front matter at the top of a markdown note.
+//
+// Edits are made line by line rather than by re-encoding the YAML, so everything
+// that isn't touched keeps the exact formatting the note was written with.
+package frontmatter
+
+import (
+	"bytes"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const delimiter = "---"
+
+type Document struct {
+	// lines of the front matter, without the delimiters; nil if the note has none
+	lines []string
+	body  []byte
+}
+
+// Parse splits a note into its front matter and body. Notes without front matter are fine.
+func Parse(data []byte) *Document {
+	text := string(data)
+	if !strings.HasPrefix(text, delimiter+"\n") {
+		return &Document{body: data}
+	}
+	rest := text[len(delimiter)+1:]
+	var lines []string
+	for {
+		line, remaining, found := strings.Cut(rest, "\n")
+		if strings.TrimRight(line, " \t\r") == delimiter {
+			return &Document{lines: lines, body: []byte(remaining)}
+		}
+		if !found {
+			// Never closed, so it wasn't front matter after all
+			return &Document{body: data}
+		}
+		lines = append(lines, line)
+		rest = remaining
+	}
+}
+
+// ReadFile parses the note at path.
+func ReadFile(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data), nil
+}
+
+func (d *Document) HasFrontMatter() bool {
+	return d.lines != nil
+}
+
+func (d *Document) Body() []byte {
+	return d.body
+}
+
+// Decode unmarshals the front matter into v.
+func (d *Document) Decode(v any) error {
+	if len(d.lines) == 0 {
+		return nil
+	}
+	return yaml.Unmarshal([]byte(strings.Join(d.lines, "\n")), v)
+}
+
+// Get returns the scalar value of a top-level key.
+func (d *Document) Get(key string) (string, bool) {
+	var values map[string]yaml.Node
+	if err := d.Decode(&values); err != nil {
+		return "", false
+	}
+	node, ok := values[key]
+	if !ok || node.Kind != yaml.ScalarNode {
+		return "", false
+	}
+	return node.Value, true
+}
+
+// Set gives a top-level key a scalar value, replacing it in place when it is already there.
+func (d *Document) Set(key string, value string) {
+	line := key + ": " + value
+	if i, end, ok := d.find(key); ok {
+		// Drop any nested lines the old value had
+		d.lines = append(d.lines[:i], append([]string{line}, d.lines[end:]...)...)
+		return
+	}
+	if d.lines == nil {
+		d.lines = []string{}
+	}
+	d.lines = append(d.lines, line)
+}
+
+// find locates a top-level key, returning its line and the line after the end of its value.
+func (d *Document) find(key string) (int, int, bool) {
+	for i, line := range d.lines {
+		if !strings.HasPrefix(line, key+":") {
+			continue
+		}
+		end := i + 1
+		for end < len(d.lines) && (strings.HasPrefix(d.lines[end], " ") || strings.HasPrefix(d.lines[end], "\t")) {
+			end++
+		}
+		return i, end, true
+	}
+	return 0, 0, false
+}
+
+func (d *Document) Bytes() []byte {
+	if d.lines == nil {
+		return d.body
+	}
+	var buf bytes.Buffer
+	buf.WriteString(delimiter + "\n")
+	for _, line := range d.lines {
+		buf.WriteString(line + "\n")
+	}
+	buf.WriteString(delimiter + "\n")
+	buf.Write(d.body)
+	return buf.Bytes()
+}
+
+// WriteFile writes the document back to path.
+func (d *Document) WriteFile(path string) error {
+	return os.WriteFile(path, d.Bytes(), 0644)
+}
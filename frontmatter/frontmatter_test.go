@@ -0,0 +1,69 @@
+package frontmatter
+
+import (
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	testCases := []struct {
+		name     string
+		note     string
+		key      string
+		value    string
+		expected string
+	}{
+		{
+			name:     "Adds a key and keeps the formatting of the rest",
+			note:     "---\nid: PROJ-1 \naliases: \ntags:\n  - 'bug'\n---\n\n# [[PROJ-1]]\n",
+			key:      "completed",
+			value:    "2024-05-08",
+			expected: "---\nid: PROJ-1 \naliases: \ntags:\n  - 'bug'\ncompleted: 2024-05-08\n---\n\n# [[PROJ-1]]\n",
+		},
+		{
+			name:     "Replaces an existing key in place",
+			note:     "---\nestimate: 1\ndue: 2024-05-06\n---\nbody\n",
+			key:      "estimate",
+			value:    "3",
+			expected: "---\nestimate: 3\ndue: 2024-05-06\n---\nbody\n",
+		},
+		{
+			name:     "Replaces nested values",
+			note:     "---\ntags:\n  - a\n  - b\nid: X\n---\n",
+			key:      "tags",
+			value:    "c",
+			expected: "---\ntags: c\nid: X\n---\n",
+		},
+		{
+			name:     "Adds front matter to a note without any",
+			note:     "# Title\n",
+			key:      "due",
+			value:    "2024-05-06",
+			expected: "---\ndue: 2024-05-06\n---\n# Title\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := Parse([]byte(tc.note))
+			doc.Set(tc.key, tc.value)
+			if got := string(doc.Bytes()); got != tc.expected {
+				t.Errorf("Expected %q, but got %q", tc.expected, got)
+			}
+			if got, ok := doc.Get(tc.key); !ok || got != tc.value {
+				t.Errorf("Expected %s to be %q, but got %q", tc.key, tc.value, got)
+			}
+		})
+	}
+}
+
+func TestParseWithoutFrontMatter(t *testing.T) {
+	for _, note := range []string{"# Title\n", "---\nnever closed\n", "text\n---\nkey: value\n---\n"} {
+		doc := Parse([]byte(note))
+		if doc.HasFrontMatter() {
+			t.Errorf("Expected %q to have no front matter", note)
+		}
+		if string(doc.Bytes()) != note {
+			t.Errorf("Expected %q to be unchanged, but got %q", note, doc.Bytes())
+		}
+	}
+}
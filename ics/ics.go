@@ -0,0 +1,164 @@
+// Package ics reads and writes the small subset of iCalendar (RFC 5545) that gnote needs.
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type Event struct {
+	UID     string
+	Summary string
+	Start   time.Time
+	End     time.Time
+	// AllDay events have DATE values; Start and End are midnights and End is exclusive.
+	AllDay bool
+	// Cancelled events, with STATUS:CANCELLED, aren't happening
+	Cancelled bool
+	// Rule is how the event repeats, from its RRULE, or nil when it doesn't
+	Rule *Rule
+	// ExDates are the starts of repeats that were deleted
+	ExDates []time.Time
+	// RecurrenceID is the start of the repeat this event replaces, when it's a moved or edited repeat
+	RecurrenceID time.Time
+}
+
+// Parse reads every VEVENT in the calendar. Times without a zone are read in loc. It fails on the first
+// event it can't read.
+func Parse(r io.Reader, loc *time.Location) ([]Event, error) {
+	events, skipped, err := ParseEvents(r, loc)
+	if err != nil {
+		return nil, err
+	}
+	if len(skipped) > 0 {
+		return nil, skipped[0]
+	}
+	return events, nil
+}
+
+// ParseEvents is Parse, but leaves out the events it can't read, like ones repeating hourly, and returns
+// why for each one rather than failing the whole calendar.
+func ParseEvents(r io.Reader, loc *time.Location) ([]Event, []error, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		events  []Event
+		skipped []error
+		event   *Event
+		// bad is the first reason the event being read can't be used
+		bad error
+	)
+	for n, line := range lines {
+		name, params, value := splitProperty(line)
+		var err error
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			event, bad = &Event{}, nil
+		case name == "END" && value == "VEVENT":
+			if event == nil {
+				return nil, nil, fmt.Errorf("line %d: END:VEVENT without BEGIN", n+1)
+			}
+			if bad != nil {
+				skipped = append(skipped, fmt.Errorf("event %q: %w", event.Summary, bad))
+				event = nil
+				continue
+			}
+			if event.End.IsZero() {
+				event.End = event.Start
+				if event.AllDay {
+					event.End = event.Start.AddDate(0, 0, 1)
+				}
+			}
+			events = append(events, *event)
+			event = nil
+		case event == nil:
+			continue
+		case name == "UID":
+			event.UID = value
+		case name == "SUMMARY":
+			event.Summary = unescape(value)
+		case name == "STATUS":
+			event.Cancelled = strings.EqualFold(value, "CANCELLED")
+		case name == "RRULE":
+			var rule Rule
+			if rule, err = ParseRule(value, loc); err == nil {
+				event.Rule = &rule
+			}
+		case name == "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				var t time.Time
+				if t, _, err = parseTime(v, params, loc); err != nil {
+					break
+				}
+				event.ExDates = append(event.ExDates, t)
+			}
+		case name == "RECURRENCE-ID":
+			event.RecurrenceID, _, err = parseTime(value, params, loc)
+		case name == "DTSTART":
+			event.Start, event.AllDay, err = parseTime(value, params, loc)
+		case name == "DTEND":
+			event.End, _, err = parseTime(value, params, loc)
+		}
+		if err != nil && bad == nil {
+			bad = fmt.Errorf("line %d: %w", n+1, err)
+		}
+	}
+	return events, skipped, nil
+}
+
+// unfold joins continuation lines, which start with a space or tab.
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// splitProperty breaks "DTSTART;TZID=Europe/London:20240105T090000" into its name, parameters and value.
+func splitProperty(line string) (string, map[string]string, string) {
+	head, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(head, ";")
+	params := map[string]string{}
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+func parseTime(value string, params map[string]string, loc *time.Location) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t.In(loc), false, err
+	}
+	if tzid, ok := params["TZID"]; ok {
+		if tz, err := time.LoadLocation(tzid); err == nil {
+			loc = tz
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+func unescape(value string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
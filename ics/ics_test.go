@@ -0,0 +1,151 @@
+package ics
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteReadsBack(t *testing.T) {
+	events := []Event{
+		{Summary: "PROJ-1; the login, again", Start: time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC), End: time.Date(2024, time.March, 4, 10, 30, 0, 0, time.UTC)},
+		{Summary: "Holiday", Start: time.Date(2024, time.March, 29, 0, 0, 0, 0, time.UTC), End: time.Date(2024, time.March, 30, 0, 0, 0, 0, time.UTC), AllDay: true},
+		{Summary: strings.Repeat("long ", 30), Start: time.Date(2024, time.March, 5, 9, 0, 0, 0, time.UTC), End: time.Date(2024, time.March, 5, 9, 15, 0, 0, time.UTC)},
+	}
+
+	var out bytes.Buffer
+	if err := Write(&out, "-//gnote//test//EN", events); err != nil {
+		t.Fatalf("Write returned an error: %v", err)
+	}
+	for _, line := range strings.Split(out.String(), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Expected lines to be folded at 75 bytes, but got %q", line)
+		}
+	}
+
+	got, err := Parse(&out, time.UTC)
+	if err != nil {
+		t.Fatalf("Parse returned an error: %v", err)
+	}
+	if len(got) != len(events) {
+		t.Fatalf("Expected %d events back, but got %+v", len(events), got)
+	}
+	for i, e := range got {
+		if e.Summary != events[i].Summary || !e.Start.Equal(events[i].Start) || !e.End.Equal(events[i].End) || e.AllDay != events[i].AllDay {
+			t.Errorf("Expected %+v, but got %+v", events[i], e)
+		}
+	}
+}
+
+func TestParseEventsSkipsBadEvents(t *testing.T) {
+	calendar := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\nRRULE:FREQ=HOURLY\r\nSUMMARY:Ping\r\nDTSTART:20240311T100000Z\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nSUMMARY:Standup\r\nDTSTART:20240311T093000Z\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	events, skipped, err := ParseEvents(strings.NewReader(calendar), time.UTC)
+	if err != nil {
+		t.Fatalf("ParseEvents returned an error: %v", err)
+	}
+	if len(events) != 1 || events[0].Summary != "Standup" {
+		t.Errorf("Expected only the standup, but got %+v", events)
+	}
+	if len(skipped) != 1 || !strings.Contains(skipped[0].Error(), `"Ping"`) || !strings.Contains(skipped[0].Error(), "HOURLY") {
+		t.Errorf("Expected the hourly event to be skipped, but got %v", skipped)
+	}
+
+	// Parse still fails on it, for holidays
+	if _, err := Parse(strings.NewReader(calendar), time.UTC); err == nil {
+		t.Error("Expected Parse to fail on the hourly event")
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip("no timezone data:", err)
+	}
+	file, err := os.Open("testdata/meetings.ics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	events, err := Parse(file, london)
+	if err != nil {
+		t.Fatalf("Parse returned an error: %v", err)
+	}
+
+	monday := time.Date(2024, time.March, 11, 0, 0, 0, 0, london)
+	got := Occurrences(events, monday, monday.AddDate(0, 0, 1))
+	expected := []struct {
+		summary string
+		start   string
+	}{
+		{"Team offsite", "00:00"},
+		// The 09:30 repeat was moved
+		{"Standup (moved)", "10:00"},
+		// 11:00 in New York, the day after the clocks went forward there
+		{"Call with the New York office", "15:00"},
+		{"Retro", "16:00"},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d events, but got %+v", len(expected), got)
+	}
+	for i, e := range got {
+		if e.Summary != expected[i].summary || e.Start.In(london).Format("15:04") != expected[i].start {
+			t.Errorf("Expected %s at %s, but got %s at %s", expected[i].summary, expected[i].start, e.Summary, e.Start.In(london).Format("15:04"))
+		}
+	}
+
+	// The deleted repeat on the 4th is left out, and the standup keeps to 09:30 in London after the clocks change
+	if week := Occurrences(events, monday.AddDate(0, 0, -7), monday.AddDate(0, 0, -6)); len(week) != 0 {
+		t.Errorf("Expected nothing on the 4th, but got %+v", week)
+	}
+	april := time.Date(2024, time.April, 1, 0, 0, 0, 0, london)
+	if after := Occurrences(events, april, april.AddDate(0, 0, 1)); len(after) != 1 || after[0].Start.In(london).Format("15:04") != "09:30" {
+		t.Errorf("Expected the standup at 09:30 on 1 April, but got %+v", after)
+	}
+}
+
+func TestRuleStarts(t *testing.T) {
+	start := time.Date(2024, time.January, 31, 9, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		rule     string
+		expected []string
+	}{
+		{"FREQ=DAILY;COUNT=3", []string{"2024-01-31", "2024-02-01", "2024-02-02"}},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;UNTIL=20240215", []string{"2024-02-01", "2024-02-13", "2024-02-15"}},
+		// Months without a 31st are skipped
+		{"FREQ=MONTHLY;COUNT=3", []string{"2024-01-31", "2024-03-31", "2024-05-31"}},
+		{"FREQ=MONTHLY;BYDAY=-1FR;COUNT=2", []string{"2024-02-23", "2024-03-29"}},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=2", []string{"2024-01-31", "2024-02-29"}},
+		{"FREQ=YEARLY;BYMONTH=3,6;BYMONTHDAY=1;COUNT=3", []string{"2024-03-01", "2024-06-01", "2025-03-01"}},
+		{"FREQ=DAILY;BYDAY=SA,SU;COUNT=2", []string{"2024-02-03", "2024-02-04"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rule, err := ParseRule(tt.rule, time.UTC)
+			if err != nil {
+				t.Fatalf("ParseRule returned an error: %v", err)
+			}
+			limit := end
+			if rule.Count > 0 {
+				limit = end.AddDate(5, 0, 0)
+			}
+			var got []string
+			for _, s := range rule.Starts(start, limit) {
+				got = append(got, s.Format("2006-01-02"))
+			}
+			if strings.Join(got, " ") != strings.Join(tt.expected, " ") {
+				t.Errorf("Expected %v, but got %v", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := ParseRule("FREQ=HOURLY", time.UTC); err == nil {
+		t.Error("Expected an error for an unsupported frequency")
+	}
+}
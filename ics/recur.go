@@ -0,0 +1,305 @@
+package ics
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rule is a recurrence rule, the parts of RRULE that calendars write for meetings: FREQ, INTERVAL,
+// COUNT, UNTIL, BYDAY, BYMONTHDAY and BYMONTH. Weeks start on Monday.
+type Rule struct {
+	Freq     string
+	Interval int
+	Count    int
+	Until    time.Time
+	// ByDay are weekdays, with N set for the Nth (or from the end when negative) in the month or year
+	ByDay      []RuleDay
+	ByMonthDay []int
+	ByMonth    []time.Month
+}
+
+type RuleDay struct {
+	N       int
+	Weekday time.Weekday
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// ParseRule reads an RRULE value like "FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20240331T000000Z".
+func ParseRule(value string, loc *time.Location) (Rule, error) {
+	rule := Rule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		key, v, _ := strings.Cut(part, "=")
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(v)
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(v)
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(v)
+		case "UNTIL":
+			var allDay bool
+			rule.Until, allDay, err = parseTime(v, nil, loc)
+			if allDay {
+				// A date is the last day repeats can fall on
+				rule.Until = rule.Until.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			}
+		case "BYDAY":
+			for _, d := range strings.Split(v, ",") {
+				weekday, ok := weekdays[strings.ToUpper(d[max(0, len(d)-2):])]
+				if !ok {
+					return Rule{}, fmt.Errorf("unknown weekday in BYDAY=%s", v)
+				}
+				n := 0
+				if prefix := d[:len(d)-2]; prefix != "" {
+					if n, err = strconv.Atoi(strings.TrimPrefix(prefix, "+")); err != nil {
+						break
+					}
+				}
+				rule.ByDay = append(rule.ByDay, RuleDay{N: n, Weekday: weekday})
+			}
+		case "BYMONTHDAY":
+			for _, d := range strings.Split(v, ",") {
+				day, convErr := strconv.Atoi(d)
+				if convErr != nil {
+					err = convErr
+					break
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, day)
+			}
+		case "BYMONTH":
+			for _, m := range strings.Split(v, ",") {
+				month, convErr := strconv.Atoi(m)
+				if convErr != nil {
+					err = convErr
+					break
+				}
+				rule.ByMonth = append(rule.ByMonth, time.Month(month))
+			}
+		}
+		if err != nil {
+			return Rule{}, fmt.Errorf("reading RRULE %s: %w", value, err)
+		}
+	}
+	switch rule.Freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return Rule{}, fmt.Errorf("unsupported RRULE frequency %q", rule.Freq)
+	}
+	if rule.Interval < 1 {
+		rule.Interval = 1
+	}
+	return rule, nil
+}
+
+// maxPeriods stops runaway rules; a daily meeting for 50 years is under it.
+const maxPeriods = 20000
+
+// Starts are the starts of the repeats of an event starting at start, up to but not including before.
+// Repeats keep the start's time of day in its location, so they follow daylight saving time.
+func (r Rule) Starts(start time.Time, before time.Time) []time.Time {
+	var starts []time.Time
+	for period := 0; period < maxPeriods; period++ {
+		for _, t := range r.candidates(start, period) {
+			if t.Before(start) {
+				continue
+			}
+			if (!r.Until.IsZero() && t.After(r.Until)) || !t.Before(before) {
+				return starts
+			}
+			starts = append(starts, t)
+			if r.Count > 0 && len(starts) == r.Count {
+				return starts
+			}
+		}
+	}
+	return starts
+}
+
+// candidates are the times in the period'th day, week, month or year from the start that the rule picks,
+// in order. There may be none, like for the 31st in a short month.
+func (r Rule) candidates(start time.Time, period int) []time.Time {
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	}
+	var dates []time.Time
+	switch r.Freq {
+	case "DAILY":
+		dates = append(dates, at(start.Year(), start.Month(), start.Day()+period*r.Interval))
+	case "WEEKLY":
+		monday := start.Day() - (int(start.Weekday())+6)%7 + period*r.Interval*7
+		if len(r.ByDay) == 0 {
+			dates = append(dates, at(start.Year(), start.Month(), monday+(int(start.Weekday())+6)%7))
+		}
+		for _, d := range r.ByDay {
+			dates = append(dates, at(start.Year(), start.Month(), monday+(int(d.Weekday)+6)%7))
+		}
+	case "MONTHLY":
+		first := at(start.Year(), start.Month()+time.Month(period*r.Interval), 1)
+		dates = r.monthDates(first, start)
+	case "YEARLY":
+		year := start.Year() + period*r.Interval
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{start.Month()}
+		}
+		for _, month := range months {
+			dates = append(dates, r.monthDates(at(year, month, 1), start)...)
+		}
+	}
+
+	var picked []time.Time
+	for _, d := range dates {
+		if r.matches(d) {
+			picked = append(picked, d)
+		}
+	}
+	sort.Slice(picked, func(i, j int) bool { return picked[i].Before(picked[j]) })
+	return picked
+}
+
+// monthDates are the days in the month starting first that BYMONTHDAY and BYDAY pick, or the start's day.
+func (r Rule) monthDates(first time.Time, start time.Time) []time.Time {
+	days := daysIn(first)
+	var dates []time.Time
+	for _, day := range r.ByMonthDay {
+		if day < 0 {
+			day = days + day + 1
+		}
+		if day >= 1 && day <= days {
+			dates = append(dates, first.AddDate(0, 0, day-1))
+		}
+	}
+	for _, d := range r.ByDay {
+		var matching []time.Time
+		for day := 1; day <= days; day++ {
+			if date := first.AddDate(0, 0, day-1); date.Weekday() == d.Weekday {
+				matching = append(matching, date)
+			}
+		}
+		switch {
+		case d.N == 0:
+			dates = append(dates, matching...)
+		case d.N > 0 && d.N <= len(matching):
+			dates = append(dates, matching[d.N-1])
+		case d.N < 0 && -d.N <= len(matching):
+			dates = append(dates, matching[len(matching)+d.N])
+		}
+	}
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 && start.Day() <= days {
+		dates = append(dates, first.AddDate(0, 0, start.Day()-1))
+	}
+	return dates
+}
+
+// matches checks the date against the BYMONTH filter, and for daily rules the BYDAY and BYMONTHDAY ones,
+// which pick days in the other frequencies instead.
+func (r Rule) matches(date time.Time) bool {
+	if len(r.ByMonth) > 0 && !containsMonth(r.ByMonth, date.Month()) {
+		return false
+	}
+	if r.Freq != "DAILY" {
+		return true
+	}
+	if len(r.ByDay) > 0 {
+		found := false
+		for _, d := range r.ByDay {
+			found = found || d.Weekday == date.Weekday()
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(r.ByMonthDay) > 0 {
+		found := false
+		for _, day := range r.ByMonthDay {
+			if day < 0 {
+				day = daysIn(date) + day + 1
+			}
+			found = found || day == date.Day()
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func containsMonth(months []time.Month, month time.Month) bool {
+	for _, m := range months {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
+
+func daysIn(date time.Time) int {
+	return time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// Occurrences are the events happening at some point from from up to to, with repeating events
+// expanded into each repeat, deleted repeats and cancelled events left out, and moved or edited
+// repeats in place of the ones they replace. They're in order of start.
+func Occurrences(events []Event, from, to time.Time) []Event {
+	// Edited repeats, by the event they belong to and the start they replace
+	replaced := map[string]bool{}
+	for _, e := range events {
+		if !e.RecurrenceID.IsZero() {
+			replaced[e.UID+"@"+e.RecurrenceID.UTC().String()] = true
+		}
+	}
+
+	var occurrences []Event
+	add := func(e Event) {
+		if e.Cancelled {
+			return
+		}
+		overlaps := e.Start.Before(to) && e.End.After(from)
+		if e.End.Equal(e.Start) {
+			overlaps = !e.Start.Before(from) && e.Start.Before(to)
+		}
+		if overlaps {
+			occurrences = append(occurrences, e)
+		}
+	}
+	for _, e := range events {
+		if e.Rule == nil || !e.RecurrenceID.IsZero() {
+			add(e)
+			continue
+		}
+		length := e.End.Sub(e.Start)
+	repeats:
+		for _, start := range e.Rule.Starts(e.Start, to) {
+			if replaced[e.UID+"@"+start.UTC().String()] {
+				continue
+			}
+			for _, ex := range e.ExDates {
+				if ex.Equal(start) || (e.AllDay && sameDate(ex, start)) {
+					continue repeats
+				}
+			}
+			repeat := e
+			repeat.Start = start
+			repeat.End = start.Add(length)
+			if e.AllDay {
+				repeat.End = start.AddDate(0, 0, int(length.Hours()/24+0.5))
+			}
+			repeat.Rule = nil
+			add(repeat)
+		}
+	}
+	sort.SliceStable(occurrences, func(i, j int) bool { return occurrences[i].Start.Before(occurrences[j].Start) })
+	return occurrences
+}
+
+func sameDate(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.Month() == b.Month() && a.Day() == b.Day()
+}
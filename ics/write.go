@@ -0,0 +1,65 @@
+package ics
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Write writes the events as a calendar, with CRLF line endings and long lines folded as RFC 5545 asks.
+// Each event's UID is made from its summary and times, so writing the same events again updates them
+// in a calendar they were imported into rather than adding copies.
+func Write(w io.Writer, prodID string, events []Event) error {
+	lines := []string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:" + prodID, "CALSCALE:GREGORIAN"}
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+	for _, e := range events {
+		sum := sha1.Sum([]byte(e.Summary + e.Start.UTC().String() + e.End.UTC().String()))
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+hex.EncodeToString(sum[:8])+"@gnote",
+			"DTSTAMP:"+stamp,
+			formatTime("DTSTART", e.Start, e.AllDay),
+			formatTime("DTEND", e.End, e.AllDay),
+			"SUMMARY:"+escape(e.Summary),
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, fold(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatTime(name string, t time.Time, allDay bool) string {
+	if allDay {
+		return fmt.Sprintf("%s;VALUE=DATE:%s", name, t.Format("20060102"))
+	}
+	return fmt.Sprintf("%s:%s", name, t.UTC().Format("20060102T150405Z"))
+}
+
+// fold breaks lines longer than 75 bytes, without splitting a character, continuing them after a space.
+func fold(line string) string {
+	var folded strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > 75 {
+			folded.WriteString("\r\n ")
+			width = 1
+		}
+		folded.WriteRune(r)
+		width += size
+	}
+	return folded.String()
+}
+
+func escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, ",", `\,`, ";", `\;`).Replace(value)
+}
@@ -0,0 +1,15 @@
+// Package markdown holds the rules for reading markdown structure that more than one part of gnote needs.
+package markdown
+
+// HeadingLevel returns the markdown heading level of the line, or 0 if it isn't a heading.
+// "## Notes" is level 2, but "#tag" and a line of only #s aren't headings.
+func HeadingLevel(line string) int {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level == len(line) || line[level] != ' ' {
+		return 0
+	}
+	return level
+}
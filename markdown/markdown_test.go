@@ -0,0 +1,20 @@
+package markdown
+
+import "testing"
+
+func TestHeadingLevel(t *testing.T) {
+	tests := map[string]int{
+		"# Monday":             1,
+		"## Morning Checklist": 2,
+		"### Notes\r":          3,
+		"#tag at the start":    0,
+		"##":                   0,
+		"- [ ] not a heading":  0,
+		"":                     0,
+	}
+	for line, expected := range tests {
+		if got := HeadingLevel(line); got != expected {
+			t.Errorf("Expected %q to be level %d, but got %d", line, expected, got)
+		}
+	}
+}
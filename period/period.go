@@ -0,0 +1,149 @@
+// Package period names the folders notes are filed into, by quarter, half, month or ISO week,
+// with quarters and halves counted from the start of the fiscal year.
+package period
+
+import (
+	"fmt"
+	"time"
+)
+
+// Calendar counts quarters and halves from FiscalYearStart. A fiscal year is named after
+// the calendar year it ends in, so with a February start, February 2024 to January 2025 is 2025.
+type Calendar struct {
+	FiscalYearStart time.Month
+}
+
+// NewCalendar checks the month the fiscal year starts in, 1 to 12, with 0 meaning January.
+func NewCalendar(fiscalYearStartMonth int) (Calendar, error) {
+	if fiscalYearStartMonth == 0 {
+		fiscalYearStartMonth = 1
+	}
+	if fiscalYearStartMonth < 1 || fiscalYearStartMonth > 12 {
+		return Calendar{}, fmt.Errorf("fiscal_year_start_month should be 1 to 12, not %d", fiscalYearStartMonth)
+	}
+	return Calendar{FiscalYearStart: time.Month(fiscalYearStartMonth)}, nil
+}
+
+// monthOfYear is how many months into the fiscal year date is, 0 to 11.
+func (c Calendar) monthOfYear(date time.Time) int {
+	return (int(date.Month()) - int(c.start()) + 12) % 12
+}
+
+func (c Calendar) start() time.Month {
+	if c.FiscalYearStart == 0 {
+		return time.January
+	}
+	return c.FiscalYearStart
+}
+
+// FiscalYear is the fiscal year date falls in, named after the calendar year it ends in.
+func (c Calendar) FiscalYear(date time.Time) int {
+	if c.start() == time.January || date.Month() < c.start() {
+		return date.Year()
+	}
+	return date.Year() + 1
+}
+
+// Quarter is the fiscal quarter of date, 1 to 4.
+func (c Calendar) Quarter(date time.Time) int {
+	return c.monthOfYear(date)/3 + 1
+}
+
+// Half is the fiscal half of date, 1 or 2.
+func (c Calendar) Half(date time.Time) int {
+	return c.monthOfYear(date)/6 + 1
+}
+
+// yearStart is the first day of fiscal year.
+func (c Calendar) yearStart(year int, loc *time.Location) time.Time {
+	if c.start() == time.January {
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	}
+	return time.Date(year-1, c.start(), 1, 0, 0, 0, 0, loc)
+}
+
+// Bucket is how notes are grouped into folders.
+type Bucket string
+
+const (
+	Quarterly Bucket = "quarter"
+	Monthly   Bucket = "month"
+	Weekly    Bucket = "week"
+	HalfYear  Bucket = "half"
+)
+
+// ParseBucket reads a bucket name, quarter when empty.
+func ParseBucket(name string) (Bucket, error) {
+	switch Bucket(name) {
+	case "":
+		return Quarterly, nil
+	case Quarterly, Monthly, Weekly, HalfYear:
+		return Bucket(name), nil
+	}
+	return "", fmt.Errorf("period_folders should be quarter, month, week or half, not %q", name)
+}
+
+// Format is the bucket's folder as a strftime format, with gnote's %f fiscal year, %q quarter and %h half.
+func (b Bucket) Format() string {
+	switch b {
+	case Monthly:
+		return "%Y-%m"
+	case Weekly:
+		return "%G-W%V"
+	case HalfYear:
+		return "%f_H%h"
+	}
+	return "%f_Q%q"
+}
+
+// Folder names the folder date is filed in: 2024_Q1, 2024-03, 2024-W10 or 2024_H1.
+func (c Calendar) Folder(b Bucket, date time.Time) string {
+	switch b {
+	case Monthly:
+		return fmt.Sprintf("%04d-%02d", date.Year(), date.Month())
+	case Weekly:
+		year, week := date.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	case HalfYear:
+		return fmt.Sprintf("%04d_H%d", c.FiscalYear(date), c.Half(date))
+	}
+	return fmt.Sprintf("%04d_Q%d", c.FiscalYear(date), c.Quarter(date))
+}
+
+// Start reads a folder name back into the first day of its period.
+func (c Calendar) Start(b Bucket, folder string, loc *time.Location) (time.Time, error) {
+	var year, n int
+	var format string
+	switch b {
+	case Monthly:
+		format = "%04d-%02d"
+	case Weekly:
+		format = "%04d-W%02d"
+	case HalfYear:
+		format = "%04d_H%d"
+	default:
+		format = "%04d_Q%d"
+	}
+	if _, err := fmt.Sscanf(folder, format, &year, &n); err != nil {
+		return time.Time{}, fmt.Errorf("%q isn't a %s folder", folder, b)
+	}
+
+	var start time.Time
+	switch b {
+	case Monthly:
+		start = time.Date(year, time.Month(n), 1, 0, 0, 0, 0, loc)
+	case Weekly:
+		// ISO week 1 is the one with 4 January in it
+		jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+		monday := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+		start = monday.AddDate(0, 0, (n-1)*7)
+	case HalfYear:
+		start = c.yearStart(year, loc).AddDate(0, (n-1)*6, 0)
+	default:
+		start = c.yearStart(year, loc).AddDate(0, (n-1)*3, 0)
+	}
+	if c.Folder(b, start) != folder {
+		return time.Time{}, fmt.Errorf("%q isn't a %s folder", folder, b)
+	}
+	return start, nil
+}
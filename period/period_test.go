@@ -0,0 +1,71 @@
+package period
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFolder(t *testing.T) {
+	february := Calendar{FiscalYearStart: time.February}
+
+	testCases := []struct {
+		name     string
+		calendar Calendar
+		bucket   Bucket
+		date     time.Time
+		expected string
+	}{
+		{"calendar quarter", Calendar{}, Quarterly, time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC), "2024_Q1"},
+		{"fiscal quarter", february, Quarterly, time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC), "2025_Q1"},
+		{"end of the fiscal year", february, Quarterly, time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC), "2024_Q4"},
+		{"fiscal half", february, HalfYear, time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC), "2025_H2"},
+		{"month", february, Monthly, time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC), "2024-03"},
+		{"ISO week", Calendar{}, Weekly, time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC), "2024-W10"},
+		{"ISO week in the year before", Calendar{}, Weekly, time.Date(2021, time.January, 2, 0, 0, 0, 0, time.UTC), "2020-W53"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.calendar.Folder(tc.bucket, tc.date)
+			if got != tc.expected {
+				t.Fatalf("Expected %s, but got %s", tc.expected, got)
+			}
+
+			start, err := tc.calendar.Start(tc.bucket, got, time.UTC)
+			if err != nil {
+				t.Fatalf("Start returned an error: %v", err)
+			}
+			if start.After(tc.date) || tc.calendar.Folder(tc.bucket, start) != got {
+				t.Errorf("Expected %s to start on or before %s, but got %s", got, tc.date.Format("2006-01-02"), start.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestStartOfFiscalQuarter(t *testing.T) {
+	start, err := Calendar{FiscalYearStart: time.February}.Start(Quarterly, "2025_Q1", time.UTC)
+	if err != nil {
+		t.Fatalf("Start returned an error: %v", err)
+	}
+	if start.Format("2006-01-02") != "2024-02-01" {
+		t.Errorf("Expected 2024-02-01, but got %s", start.Format("2006-01-02"))
+	}
+
+	for _, folder := range []string{"2024_Q5", "2024-13", "notes"} {
+		if _, err := (Calendar{}).Start(Quarterly, folder, time.UTC); err == nil {
+			t.Errorf("Expected %q to be rejected", folder)
+		}
+	}
+}
+
+func TestSettings(t *testing.T) {
+	if _, err := NewCalendar(13); err == nil {
+		t.Error("Expected month 13 to be rejected")
+	}
+	if c, err := NewCalendar(0); err != nil || c.FiscalYearStart != time.January {
+		t.Errorf("Expected an unset month to mean January, but got %v, %v", c, err)
+	}
+	if _, err := ParseBucket("fortnight"); err == nil {
+		t.Error("Expected an unknown bucket to be rejected")
+	}
+}
@@ -0,0 +1,164 @@
+// Package rollup sums up notes over a stretch of time: the tasks in day notes, the projects
+// started and finished, and how estimates held up. Weekly and periodic reviews and reports share it.
+package rollup
+
+import (
+	"fmt"
+	"time"
+
+	"gnote/daynote"
+	"gnote/tasks"
+	"gnote/workday"
+)
+
+// Range is the days from Start up to, but not including, End.
+type Range struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (r Range) Contains(t time.Time) bool {
+	return !t.IsZero() && !t.Before(r.Start) && t.Before(r.End)
+}
+
+// Last is the final day in the range.
+func (r Range) Last() time.Time {
+	return r.End.AddDate(0, 0, -1)
+}
+
+// Task is a task in a day note.
+type Task struct {
+	tasks.Task
+	Note daynote.Note
+}
+
+// Tasks reads the tasks from each note, in order.
+func Tasks(notes []daynote.Note) ([]Task, error) {
+	var all []Task
+	for _, note := range notes {
+		noteTasks, err := tasks.ParseFile(note.Path, note.Date.Location())
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range noteTasks {
+			all = append(all, Task{Task: task, Note: note})
+		}
+	}
+	return all, nil
+}
+
+// Project is a project or archived project's dates, from its description note.
+type Project struct {
+	Name      string
+	Path      string
+	Archived  bool
+	Started   time.Time
+	Completed time.Time
+}
+
+// StartedIn returns the projects started within r, in the order given.
+func StartedIn(projects []Project, r Range) []Project {
+	var started []Project
+	for _, p := range projects {
+		if r.Contains(p.Started) {
+			started = append(started, p)
+		}
+	}
+	return started
+}
+
+// ArchivedIn returns the archived projects completed within r, in the order given.
+func ArchivedIn(projects []Project, r Range) []Project {
+	var archived []Project
+	for _, p := range projects {
+		if p.Archived && r.Contains(p.Completed) {
+			archived = append(archived, p)
+		}
+	}
+	return archived
+}
+
+// Estimate is how long a ticket was expected to take, in working days, and how it went.
+type Estimate struct {
+	Ticket    string
+	Estimate  int
+	Started   time.Time
+	Due       time.Time
+	Completed time.Time
+}
+
+func (e Estimate) Done() bool {
+	return !e.Completed.IsZero()
+}
+
+func (e Estimate) OnTime() bool {
+	return e.Done() && !e.Completed.After(e.Due)
+}
+
+// Actual is how many working days the ticket took.
+func (e Estimate) Actual(calendar *workday.Calendar) int {
+	return calendar.WorkingDaysBetween(e.Started, e.Completed)
+}
+
+// Slip is how many working days over (or under) the estimate the ticket took.
+func (e Estimate) Slip(calendar *workday.Calendar) int {
+	return e.Actual(calendar) - e.Estimate
+}
+
+// CompletedIn returns the estimates for tickets completed within r, in the order given.
+func CompletedIn(estimates []Estimate, r Range) []Estimate {
+	var completed []Estimate
+	for _, e := range estimates {
+		if r.Contains(e.Completed) {
+			completed = append(completed, e)
+		}
+	}
+	return completed
+}
+
+// Tally counts how a group of finished estimates went.
+type Tally struct {
+	Done   int
+	OnTime int
+	Slip   int
+}
+
+func (t *Tally) Add(e Estimate, calendar *workday.Calendar) {
+	t.Done++
+	t.Slip += e.Slip(calendar)
+	if e.OnTime() {
+		t.OnTime++
+	}
+}
+
+// HitRate is the share finished on time, like 75%, or - when nothing is finished.
+func (t Tally) HitRate() string {
+	if t.Done == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d%%", t.OnTime*100/t.Done)
+}
+
+// AverageSlip is the mean working days over the estimate, like +1.5, or - when nothing is finished.
+func (t Tally) AverageSlip() string {
+	if t.Done == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%+.1f", float64(t.Slip)/float64(t.Done))
+}
+
+// TallyBy tallies the finished estimates in groups, keyed by key.
+func TallyBy[K comparable](estimates []Estimate, calendar *workday.Calendar, key func(Estimate) K) map[K]*Tally {
+	tallies := map[K]*Tally{}
+	for _, e := range estimates {
+		if !e.Done() {
+			continue
+		}
+		k := key(e)
+		if tallies[k] == nil {
+			tallies[k] = &Tally{}
+		}
+		tallies[k].Add(e, calendar)
+	}
+	return tallies
+}
@@ -0,0 +1,90 @@
+package rollup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gnote/daynote"
+	"gnote/tasks"
+	"gnote/workday"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestTasks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "3-4-2024.md")
+	content := "# Monday\n\n## Morning Checklist\n\n- [x] check email\n- [ ] check Slack\n\n## Today\n\n- [X] ship it\n  * [ ] nested\n- [ ]\n- not a task\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Tasks([]daynote.Note{{Date: date(2024, time.March, 4), Path: path}})
+	if err != nil {
+		t.Fatalf("Tasks returned an error: %v", err)
+	}
+	expected := []tasks.Task{
+		{Section: "Morning Checklist", Text: "check email", Status: tasks.Done},
+		{Section: "Morning Checklist", Text: "check Slack", Status: tasks.Open},
+		{Section: "Today", Text: "ship it", Status: tasks.Done},
+		{Section: "Today", Text: "nested", Status: tasks.Open},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d tasks, but got %+v", len(expected), got)
+	}
+	for i, task := range got {
+		if task.Section != expected[i].Section || task.Text != expected[i].Text || task.Status != expected[i].Status || task.Note.Path != path {
+			t.Errorf("Expected %+v, but got %+v", expected[i], task)
+		}
+	}
+}
+
+func TestProjectsInRange(t *testing.T) {
+	march := Range{Start: date(2024, time.March, 1), End: date(2024, time.April, 1)}
+	projects := []Project{
+		{Name: "NEW-1", Started: date(2024, time.March, 1)},
+		{Name: "OLD-1", Started: date(2024, time.February, 1), Completed: date(2024, time.March, 31), Archived: true},
+		{Name: "DONE-1", Started: date(2024, time.March, 5), Completed: date(2024, time.March, 20)},
+		{Name: "NEXT-1", Started: date(2024, time.April, 1), Completed: date(2024, time.April, 2), Archived: true},
+	}
+
+	names := func(projects []Project) []string {
+		var result []string
+		for _, p := range projects {
+			result = append(result, p.Name)
+		}
+		return result
+	}
+	if got := names(StartedIn(projects, march)); len(got) != 2 || got[0] != "NEW-1" || got[1] != "DONE-1" {
+		t.Errorf("Expected NEW-1 and DONE-1 to have started in March, but got %v", got)
+	}
+	if got := names(ArchivedIn(projects, march)); len(got) != 1 || got[0] != "OLD-1" {
+		t.Errorf("Expected OLD-1 to have been archived in March, but got %v", got)
+	}
+}
+
+func TestTallyBy(t *testing.T) {
+	calendar := workday.New()
+	estimates := []Estimate{
+		// Monday to Wednesday is 2 working days, due Wednesday
+		{Ticket: "A-1", Estimate: 2, Started: date(2024, time.March, 4), Due: date(2024, time.March, 6), Completed: date(2024, time.March, 6)},
+		// Finished the next Monday, 3 working days late
+		{Ticket: "A-2", Estimate: 2, Started: date(2024, time.March, 4), Due: date(2024, time.March, 6), Completed: date(2024, time.March, 11)},
+		{Ticket: "A-3", Estimate: 1, Started: date(2024, time.March, 4), Due: date(2024, time.March, 5)},
+	}
+
+	tallies := TallyBy(estimates, calendar, func(e Estimate) int { return e.Estimate })
+	if len(tallies) != 1 {
+		t.Fatalf("Expected only finished estimates to be tallied, but got %v", tallies)
+	}
+	tally := tallies[2]
+	if tally.Done != 2 || tally.OnTime != 1 || tally.HitRate() != "50%" || tally.AverageSlip() != "+1.5" {
+		t.Errorf("Expected 2 done, 1 on time, 50%% and +1.5, but got %+v, %s and %s", tally, tally.HitRate(), tally.AverageSlip())
+	}
+	if (Tally{}).HitRate() != "-" || (Tally{}).AverageSlip() != "-" {
+		t.Error("Expected an empty tally to show -")
+	}
+}
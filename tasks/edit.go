@@ -0,0 +1,141 @@
+package tasks
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gnote/markdown"
+)
+
+// idLength is how many hex digits of the hash make up an ID, plenty to tell a vault's tasks apart.
+const idLength = 7
+
+// ID identifies a task by the note it's in and its text, so it stays the same when lines are added
+// around it or its status changes. note should be the same for every run, like the path from the vault.
+func ID(note string, task Task) string {
+	sum := sha1.Sum([]byte(note + "\x00" + task.Text))
+	return hex.EncodeToString(sum[:])[:idLength]
+}
+
+var emptyCheckboxPattern = regexp.MustCompile(`^\s*[-*+] \[ \]\s*$`)
+
+// splitLines splits content after each newline, so joining the lines gives back the same bytes.
+func splitLines(content []byte) [][]byte {
+	return bytes.SplitAfter(content, []byte("\n"))
+}
+
+// SetStatus changes the mark in the checkbox on line, from 1, leaving every other byte of the note as it was.
+func SetStatus(content []byte, line int, status Status) ([]byte, error) {
+	lines := splitLines(content)
+	if line < 1 || line > len(lines) {
+		return nil, fmt.Errorf("line %d is not in the note", line)
+	}
+	match := checkboxPattern.FindSubmatchIndex(lines[line-1])
+	if match == nil {
+		return nil, fmt.Errorf("line %d is not a task", line)
+	}
+
+	var updated bytes.Buffer
+	for i, l := range lines {
+		if i == line-1 {
+			updated.Write(l[:match[2]])
+			updated.WriteString(status.Mark())
+			updated.Write(l[match[3]:])
+			continue
+		}
+		updated.Write(l)
+	}
+	return updated.Bytes(), nil
+}
+
+// Append adds an open task to the end of the list under heading, like "## TODO", written the way the
+// list's last task is. An empty "- [ ]" placeholder is filled in rather than followed. The section
+// is added to the end of the note when it isn't there. The rest of the note is left as it was.
+func Append(content []byte, heading string, text string) []byte {
+	lines := splitLines(content)
+	if len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	newline := "\n"
+	if len(lines) > 0 && bytes.HasSuffix(lines[0], []byte("\r\n")) {
+		newline = "\r\n"
+	}
+
+	start, end := -1, len(lines)
+	for i, l := range lines {
+		trimmed := strings.TrimRight(string(l), " \t\r\n")
+		if start < 0 {
+			if trimmed == heading {
+				start = i
+			}
+			continue
+		}
+		if level := markdown.HeadingLevel(trimmed); level > 0 && level <= markdown.HeadingLevel(heading) {
+			end = i
+			break
+		}
+	}
+
+	if start < 0 {
+		var updated bytes.Buffer
+		updated.Write(content)
+		if len(content) > 0 && !bytes.HasSuffix(content, []byte("\n")) {
+			updated.WriteString(newline)
+		}
+		if len(content) > 0 {
+			updated.WriteString(newline)
+		}
+		updated.WriteString(heading + newline + newline + "- [ ] " + text + newline)
+		return updated.Bytes()
+	}
+
+	// Find the last task in the section, to follow it
+	last := -1
+	for i := start + 1; i < end; i++ {
+		if checkboxPattern.Match(lines[i]) || emptyCheckboxPattern.Match(lines[i]) {
+			last = i
+		}
+	}
+
+	var insert []byte
+	at := start + 1
+	replace := false
+	if last < 0 {
+		// An empty section gets a blank line after the heading, then the task
+		insert = []byte(newline + "- [ ] " + text + newline)
+	} else {
+		l := lines[last]
+		prefix := l[:bytes.Index(l, []byte("["))]
+		insert = []byte(string(prefix) + "[ ] " + text + newline)
+		at = last + 1
+		replace = emptyCheckboxPattern.Match(l)
+		if replace {
+			at = last
+		}
+	}
+
+	var updated bytes.Buffer
+	for i, l := range lines {
+		if i == at {
+			updated.Write(insert)
+			if replace {
+				continue
+			}
+		}
+		if i == len(lines)-1 && at > i && !bytes.HasSuffix(l, []byte("\n")) {
+			// The note ended mid-line, so end it before adding the task
+			updated.Write(l)
+			updated.WriteString(newline)
+			continue
+		}
+		updated.Write(l)
+	}
+	if at >= len(lines) {
+		updated.Write(insert)
+	}
+	return updated.Bytes()
+}
@@ -0,0 +1,188 @@
+// Package tasks reads markdown checkboxes: - [ ] open, - [x] done, - [>] deferred and - [-] cancelled,
+// along with the due dates, priorities and tags written on them.
+package tasks
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"gnote/markdown"
+)
+
+type Status string
+
+const (
+	Open      Status = "open"
+	Done      Status = "done"
+	Deferred  Status = "deferred"
+	Cancelled Status = "cancelled"
+)
+
+var statusMarks = map[string]Status{
+	" ": Open,
+	"x": Done,
+	"X": Done,
+	">": Deferred,
+	"-": Cancelled,
+}
+
+// Mark is the character between the brackets for the status.
+func (s Status) Mark() string {
+	switch s {
+	case Done:
+		return "x"
+	case Deferred:
+		return ">"
+	case Cancelled:
+		return "-"
+	}
+	return " "
+}
+
+// ParseStatus reads a status name as used on the command line.
+func ParseStatus(name string) (Status, bool) {
+	for _, status := range []Status{Open, Done, Deferred, Cancelled} {
+		if strings.EqualFold(name, string(status)) {
+			return status, true
+		}
+	}
+	return "", false
+}
+
+// Priority orders tasks from Lowest to Highest, with None in the middle like the Obsidian Tasks plugin.
+type Priority int
+
+const (
+	Lowest  Priority = -2
+	Low     Priority = -1
+	None    Priority = 0
+	Medium  Priority = 1
+	High    Priority = 2
+	Highest Priority = 3
+)
+
+var priorityNames = map[Priority]string{
+	Lowest:  "lowest",
+	Low:     "low",
+	None:    "",
+	Medium:  "medium",
+	High:    "high",
+	Highest: "highest",
+}
+
+func (p Priority) String() string {
+	return priorityNames[p]
+}
+
+// priorityEmoji are the Obsidian Tasks plugin's priority markers.
+var priorityEmoji = map[string]Priority{
+	"⏬": Lowest,
+	"🔽": Low,
+	"🔼": Medium,
+	"⏫": High,
+	"🔺": Highest,
+}
+
+type Task struct {
+	// Line is the task's line number in the note, from 1
+	Line   int
+	Status Status
+	// Text is everything after the checkbox, as written
+	Text string
+	// Description is the text without the due date and priority
+	Description string
+	Due         time.Time
+	Priority    Priority
+	// Tags are the #tags on the task, without the #
+	Tags []string
+	// Section is the heading the task is under, without the #s
+	Section string
+}
+
+func (t Task) HasTag(tag string) bool {
+	tag = strings.TrimPrefix(tag, "#")
+	for _, own := range t.Tags {
+		if strings.EqualFold(own, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	checkboxPattern = regexp.MustCompile(`^\s*[-*+] \[(.)\] (.*\S)`)
+	duePattern      = regexp.MustCompile(`(?:📅\s*|\bdue:\s*)(\d{4}-\d{2}-\d{2})`)
+	priorityPattern = regexp.MustCompile(`\bpriority:\s*(lowest|low|medium|high|highest)\b`)
+	tagPattern      = regexp.MustCompile(`(?:^|\s)#([\p{L}\p{N}_/-]+)`)
+)
+
+// Parse reads the tasks from a note. Checkboxes with nothing after them, or with a mark other than
+// space, x, > or -, aren't tasks.
+func Parse(content []byte, loc *time.Location) []Task {
+	var tasks []Task
+	section := ""
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if level := markdown.HeadingLevel(text); level > 0 {
+			section = strings.TrimSpace(text[level:])
+			continue
+		}
+		match := checkboxPattern.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+		status, ok := statusMarks[match[1]]
+		if !ok {
+			continue
+		}
+		tasks = append(tasks, parseTask(line, status, match[2], section, loc))
+	}
+	return tasks
+}
+
+// ParseFile reads the tasks from the note at path.
+func ParseFile(path string, loc *time.Location) ([]Task, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(content, loc), nil
+}
+
+func parseTask(line int, status Status, text string, section string, loc *time.Location) Task {
+	task := Task{Line: line, Status: status, Text: text, Section: section, Priority: None}
+	description := text
+
+	if match := duePattern.FindStringSubmatch(text); match != nil {
+		if due, err := time.ParseInLocation("2006-01-02", match[1], loc); err == nil {
+			task.Due = due
+			description = strings.Replace(description, match[0], "", 1)
+		}
+	}
+	for emoji, priority := range priorityEmoji {
+		if strings.Contains(description, emoji) {
+			task.Priority = priority
+			description = strings.Replace(description, emoji, "", 1)
+			break
+		}
+	}
+	if match := priorityPattern.FindStringSubmatch(description); match != nil {
+		for priority, name := range priorityNames {
+			if name == match[1] {
+				task.Priority = priority
+			}
+		}
+		description = strings.Replace(description, match[0], "", 1)
+	}
+	for _, match := range tagPattern.FindAllStringSubmatch(text, -1) {
+		task.Tags = append(task.Tags, match[1])
+	}
+
+	task.Description = strings.Join(strings.Fields(description), " ")
+	return task
+}
@@ -0,0 +1,116 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	content := "# Monday\n\n## Today\n\n- [ ] Write it up 📅 2024-03-08 ⏫ #writing\n- [x] Fix the login\n" +
+		"* [>] Follow up due:2024-03-11 priority:low\n- [-] Drop the cache\n- [?] Not a task\n- [ ]\n- not a task\n"
+	got := Parse([]byte(content), time.UTC)
+
+	expected := []Task{
+		{Line: 5, Status: Open, Description: "Write it up #writing", Due: time.Date(2024, time.March, 8, 0, 0, 0, 0, time.UTC), Priority: High, Tags: []string{"writing"}},
+		{Line: 6, Status: Done, Description: "Fix the login"},
+		{Line: 7, Status: Deferred, Description: "Follow up", Due: time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC), Priority: Low},
+		{Line: 8, Status: Cancelled, Description: "Drop the cache"},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d tasks, but got %+v", len(expected), got)
+	}
+	for i, task := range got {
+		want := expected[i]
+		if task.Line != want.Line || task.Status != want.Status || task.Description != want.Description ||
+			!task.Due.Equal(want.Due) || task.Priority != want.Priority || task.Section != "Today" || len(task.Tags) != len(want.Tags) {
+			t.Errorf("Expected %+v, but got %+v", want, task)
+		}
+	}
+	if !got[0].HasTag("#Writing") {
+		t.Errorf("Expected the first task to be tagged writing, but got %v", got[0].Tags)
+	}
+}
+
+func TestStatusMarks(t *testing.T) {
+	for _, status := range []Status{Open, Done, Deferred, Cancelled} {
+		parsed := Parse([]byte("- ["+status.Mark()+"] task\n"), time.UTC)
+		if len(parsed) != 1 || parsed[0].Status != status {
+			t.Errorf("Expected %q to read back as %s, but got %+v", status.Mark(), status, parsed)
+		}
+		if named, ok := ParseStatus(string(status)); !ok || named != status {
+			t.Errorf("Expected ParseStatus(%q) to be %s", status, status)
+		}
+	}
+}
+
+func TestID(t *testing.T) {
+	before := Parse([]byte("- [ ] Write it up\n"), time.UTC)[0]
+	after := Parse([]byte("# Today\n\n- [x] Write it up\n"), time.UTC)[0]
+	if ID("d/3-4-2024.md", before) != ID("d/3-4-2024.md", after) {
+		t.Error("Expected the ID to stay the same when the task moves or is ticked off")
+	}
+	if ID("d/3-4-2024.md", before) == ID("d/3-5-2024.md", before) {
+		t.Error("Expected the same task in another note to have another ID")
+	}
+}
+
+func TestSetStatus(t *testing.T) {
+	content := "# Today\r\n\r\n  * [ ] Write it up 📅 2024-03-08\r\n- [ ] Ship it"
+	updated, err := SetStatus([]byte(content), 3, Done)
+	if err != nil {
+		t.Fatalf("SetStatus returned an error: %v", err)
+	}
+	if want := "# Today\r\n\r\n  * [x] Write it up 📅 2024-03-08\r\n- [ ] Ship it"; string(updated) != want {
+		t.Errorf("Expected %q, but got %q", want, updated)
+	}
+	if _, err := SetStatus([]byte(content), 1, Done); err == nil {
+		t.Error("Expected an error setting the status of a heading")
+	}
+}
+
+func TestAppend(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		heading  string
+		expected string
+	}{
+		{
+			name:     "after the last task",
+			content:  "# [[PROJ-1]] - TODO\n\n## TODO\n\n  - [ ] Investigate\n  - [x] Branch\n\n## Later\n\n- [ ] Other\n",
+			heading:  "## TODO",
+			expected: "# [[PROJ-1]] - TODO\n\n## TODO\n\n  - [ ] Investigate\n  - [x] Branch\n  - [ ] Write tests\n\n## Later\n\n- [ ] Other\n",
+		},
+		{
+			name:     "fills in the placeholder",
+			content:  "## What do you want to accomplish today?\n\n- [ ]\n\n## Commits\n",
+			heading:  "## What do you want to accomplish today?",
+			expected: "## What do you want to accomplish today?\n\n- [ ] Write tests\n\n## Commits\n",
+		},
+		{
+			name:     "empty section at the end",
+			content:  "# Notes\n\n## TODO",
+			heading:  "## TODO",
+			expected: "# Notes\n\n## TODO\n\n- [ ] Write tests\n",
+		},
+		{
+			name:     "no section",
+			content:  "# Notes\r\n",
+			heading:  "## TODO",
+			expected: "# Notes\r\n\r\n## TODO\r\n\r\n- [ ] Write tests\r\n",
+		},
+		{
+			name:     "new note",
+			content:  "",
+			heading:  "## TODO",
+			expected: "## TODO\n\n- [ ] Write tests\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(Append([]byte(tt.content), tt.heading, "Write tests")); got != tt.expected {
+				t.Errorf("Expected %q, but got %q", tt.expected, got)
+			}
+		})
+	}
+}
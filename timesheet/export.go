@@ -0,0 +1,257 @@
+package timesheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"time"
+
+	"gnote/ics"
+)
+
+// Rounded returns a copy of the sheet with each ticket's time on each day rounded to the nearest unit,
+// the way timesheets are filled in, so the totals add up to what's on the sheet. A zero unit leaves it alone.
+func (s *Sheet) Rounded(unit time.Duration) *Sheet {
+	rounded := &Sheet{Days: s.Days, Tickets: s.Tickets, hours: map[string]map[time.Time]time.Duration{}}
+	for ticket, days := range s.hours {
+		rounded.hours[ticket] = map[time.Time]time.Duration{}
+		for date, d := range days {
+			if unit > 0 {
+				d = d.Round(unit)
+			}
+			rounded.hours[ticket][date] = d
+		}
+	}
+	return rounded
+}
+
+// Column is one column of an export with a row for each ticket on each day.
+type Column struct {
+	// Field is date, weekday, ticket, hours or minutes
+	Field string
+	// Name is the column's heading
+	Name string
+	// Format is the Go time layout for a date; 2006-01-02 when empty
+	Format string
+}
+
+// DefaultColumns are the columns when none are configured.
+var DefaultColumns = []Column{
+	{Field: "date", Name: "Date"},
+	{Field: "ticket", Name: "Ticket"},
+	{Field: "hours", Name: "Hours"},
+}
+
+// Table is an export before it's written out. Cells are strings, or hours as float64 and minutes as int.
+type Table struct {
+	Header []string
+	Rows   [][]any
+}
+
+// Entries is a row for each ticket on each day it was worked on, with the given columns.
+func (s *Sheet) Entries(columns []Column) (Table, error) {
+	table := Table{}
+	for _, column := range columns {
+		switch column.Field {
+		case "date", "weekday", "ticket", "hours", "minutes":
+		default:
+			return Table{}, fmt.Errorf("unknown timesheet column field %q, use date, weekday, ticket, hours or minutes", column.Field)
+		}
+		name := column.Name
+		if name == "" {
+			name = column.Field
+		}
+		table.Header = append(table.Header, name)
+	}
+
+	for _, date := range s.Days {
+		for _, ticket := range s.Tickets {
+			d := s.Time(ticket, date)
+			if d == 0 {
+				continue
+			}
+			var row []any
+			for _, column := range columns {
+				switch column.Field {
+				case "date":
+					layout := column.Format
+					if layout == "" {
+						layout = "2006-01-02"
+					}
+					row = append(row, date.Format(layout))
+				case "weekday":
+					row = append(row, date.Weekday().String())
+				case "ticket":
+					row = append(row, ticket)
+				case "hours":
+					row = append(row, hours(d))
+				case "minutes":
+					row = append(row, int(d.Minutes()))
+				}
+			}
+			table.Rows = append(table.Rows, row)
+		}
+	}
+	return table, nil
+}
+
+// ByTicket is a row for each ticket with a column for each day, labelled with dayLayout, then the totals.
+func (s *Sheet) ByTicket(dayLayout string) Table {
+	table := Table{Header: []string{"ticket"}}
+	for _, date := range s.Days {
+		table.Header = append(table.Header, date.Format(dayLayout))
+	}
+	table.Header = append(table.Header, "total")
+
+	for _, ticket := range s.Tickets {
+		row := []any{ticket}
+		for _, date := range s.Days {
+			row = append(row, hours(s.Time(ticket, date)))
+		}
+		table.Rows = append(table.Rows, append(row, hours(s.TicketTotal(ticket))))
+	}
+	totals := []any{"Total"}
+	for _, date := range s.Days {
+		totals = append(totals, hours(s.DayTotal(date)))
+	}
+	table.Rows = append(table.Rows, append(totals, hours(s.Total())))
+	return table
+}
+
+// ByDay is a row for each day, labelled with dayLayout, with a column for each ticket, then the totals.
+func (s *Sheet) ByDay(dayLayout string) Table {
+	table := Table{Header: append(append([]string{"day"}, s.Tickets...), "total")}
+	for _, date := range s.Days {
+		row := []any{date.Format(dayLayout)}
+		for _, ticket := range s.Tickets {
+			row = append(row, hours(s.Time(ticket, date)))
+		}
+		table.Rows = append(table.Rows, append(row, hours(s.DayTotal(date))))
+	}
+	totals := []any{"Total"}
+	for _, ticket := range s.Tickets {
+		totals = append(totals, hours(s.TicketTotal(ticket)))
+	}
+	table.Rows = append(table.Rows, append(totals, hours(s.Total())))
+	return table
+}
+
+// hours is a duration in hours, to two decimal places.
+func hours(d time.Duration) float64 {
+	return math.Round(d.Hours()*100) / 100
+}
+
+// Cell formats a cell of a table, with hours to two decimal places.
+func Cell(value any) string {
+	if h, ok := value.(float64); ok {
+		return fmt.Sprintf("%.2f", h)
+	}
+	return fmt.Sprint(value)
+}
+
+func WriteCSV(w io.Writer, table Table) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(table.Header); err != nil {
+		return err
+	}
+	for _, row := range table.Rows {
+		record := make([]string, len(row))
+		for i, value := range row {
+			record[i] = Cell(value)
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// WriteJSON writes the table as an array of objects keyed by the header, keeping the columns in order.
+func WriteJSON(w io.Writer, table Table) error {
+	var out bytes.Buffer
+	out.WriteString("[")
+	for i, row := range table.Rows {
+		if i > 0 {
+			out.WriteString(",")
+		}
+		out.WriteString("\n  {")
+		for j, value := range row {
+			key, err := json.Marshal(table.Header[j])
+			if err != nil {
+				return err
+			}
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			if j > 0 {
+				out.WriteString(", ")
+			}
+			fmt.Fprintf(&out, "%s: %s", key, encoded)
+		}
+		out.WriteString("}")
+	}
+	if len(table.Rows) > 0 {
+		out.WriteString("\n")
+	}
+	out.WriteString("]\n")
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+var htmlTemplate = template.Must(template.New("timesheet").Funcs(template.FuncMap{"cell": Cell}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.8em; }
+td { text-align: right; }
+td:first-child, th:first-child { text-align: left; }
+tr:last-child { font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+<p>{{ .Total }} hours in all.</p>
+<table>
+<tr>{{ range .Table.Header }}<th>{{ . }}</th>{{ end }}</tr>
+{{- range .Table.Rows }}
+<tr>{{ range . }}<td>{{ cell . }}</td>{{ end }}</tr>
+{{- end }}
+</table>
+</body>
+</html>
+`))
+
+// WriteHTML writes the table as a page to share, headed by title.
+func WriteHTML(w io.Writer, title string, table Table, total time.Duration) error {
+	return htmlTemplate.Execute(w, struct {
+		Title string
+		Total string
+		Table Table
+	}{title, Hours(total), table})
+}
+
+// Events are the spans as calendar events, starting and ending on the nearest unit.
+func Events(spans []Span, unit time.Duration) []ics.Event {
+	var events []ics.Event
+	for _, s := range spans {
+		start, end := s.Start, s.End
+		if unit > 0 {
+			start, end = start.Round(unit), end.Round(unit)
+		}
+		if !end.After(start) {
+			continue
+		}
+		events = append(events, ics.Event{Summary: s.Ticket, Start: start, End: end})
+	}
+	return events
+}
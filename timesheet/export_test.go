@@ -0,0 +1,83 @@
+package timesheet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"gnote/clock"
+)
+
+func exportSheet() *Sheet {
+	spans := []Span{
+		// 1h20m rounds to 1h15m
+		{Ticket: "PROJ-1", Start: at(4, 9, 0), End: at(4, 10, 20)},
+		// 38m rounds to 45m
+		{Ticket: "PROJ-2", Start: at(4, 11, 0), End: at(4, 11, 38)},
+		{Ticket: "PROJ-1", Start: at(5, 9, 0), End: at(5, 9, 7)},
+	}
+	return NewSheet(spans, at(4, 0, 0), 2, clock.Day{Location: time.UTC}).Rounded(15 * time.Minute)
+}
+
+func TestEntries(t *testing.T) {
+	table, err := exportSheet().Entries([]Column{
+		{Field: "date", Name: "Work Date", Format: "02/01/2006"},
+		{Field: "ticket", Name: "Project Code"},
+		{Field: "minutes", Name: "Minutes"},
+	})
+	if err != nil {
+		t.Fatalf("Entries returned an error: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := WriteCSV(&out, table); err != nil {
+		t.Fatal(err)
+	}
+	// PROJ-1's 7 minutes on Tuesday round to nothing
+	want := "Work Date,Project Code,Minutes\n04/03/2024,PROJ-1,75\n04/03/2024,PROJ-2,45\n"
+	if out.String() != want {
+		t.Errorf("Expected\n%s\nbut got\n%s", want, out.String())
+	}
+
+	if _, err := exportSheet().Entries([]Column{{Field: "cost"}}); err == nil {
+		t.Error("Expected an error for an unknown field")
+	}
+}
+
+func TestPivots(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteJSON(&out, exportSheet().ByDay("Mon")); err != nil {
+		t.Fatal(err)
+	}
+	want := `[
+  {"day": "Mon", "PROJ-1": 1.25, "PROJ-2": 0.75, "total": 2},
+  {"day": "Tue", "PROJ-1": 0, "PROJ-2": 0, "total": 0},
+  {"day": "Total", "PROJ-1": 1.25, "PROJ-2": 0.75, "total": 2}
+]
+`
+	if out.String() != want {
+		t.Errorf("Expected\n%s\nbut got\n%s", want, out.String())
+	}
+
+	out.Reset()
+	sheet := exportSheet()
+	if err := WriteHTML(&out, "Timesheet", sheet.ByTicket("Mon"), sheet.Total()); err != nil {
+		t.Fatal(err)
+	}
+	for _, cell := range []string{"<h1>Timesheet</h1>", "<p>2.00 hours in all.</p>", "<tr><td>PROJ-1</td><td>1.25</td><td>0.00</td><td>1.25</td></tr>"} {
+		if !strings.Contains(out.String(), cell) {
+			t.Errorf("Expected the page to contain %q, but got:\n%s", cell, out.String())
+		}
+	}
+}
+
+func TestEvents(t *testing.T) {
+	events := Events([]Span{
+		{Ticket: "PROJ-1", Start: at(4, 9, 2), End: at(4, 10, 20)},
+		{Ticket: "PROJ-2", Start: at(4, 11, 0), End: at(4, 11, 5)},
+	}, 15*time.Minute)
+	if len(events) != 1 || !events[0].Start.Equal(at(4, 9, 0)) || !events[0].End.Equal(at(4, 10, 15)) || events[0].Summary != "PROJ-1" {
+		t.Errorf("Expected one event for PROJ-1 from 09:00 to 10:15, but got %+v", events)
+	}
+}
@@ -0,0 +1,51 @@
+package timesheet
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Timer is the timer that's running, kept between runs of `gnote log`.
+type Timer struct {
+	Ticket  string    `yaml:"ticket"`
+	Started time.Time `yaml:"started"`
+}
+
+// ReadTimer reads the running timer from path, returning false when none is running.
+func ReadTimer(path string) (Timer, bool, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Timer{}, false, nil
+	}
+	if err != nil {
+		return Timer{}, false, err
+	}
+	var timer Timer
+	if err := yaml.Unmarshal(content, &timer); err != nil {
+		return Timer{}, false, err
+	}
+	return timer, timer.Ticket != "", nil
+}
+
+// WriteTimer records the running timer at path.
+func WriteTimer(path string, timer Timer) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	content, err := yaml.Marshal(timer)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+// ClearTimer records that no timer is running.
+func ClearTimer(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+// Package timesheet reads time tracking from the Log section of day notes, where `gnote log` writes
+// entries like "- 09:30 start [[PROJ-1]]", and adds up the time spent on each ticket.
+package timesheet
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gnote/clock"
+)
+
+// Heading is the day note section log entries are written under.
+const Heading = "## Log"
+
+type Kind string
+
+const (
+	Start Kind = "start"
+	Stop  Kind = "stop"
+	Note  Kind = "note"
+)
+
+// Entry is one line in a day note's log.
+type Entry struct {
+	Time   time.Time
+	Kind   Kind
+	Ticket string
+	// Text is what was noted, for Note entries
+	Text string
+}
+
+// String is the entry as it's written in the log, without the date, which the note gives.
+func (e Entry) String() string {
+	stamp := e.Time.Format("15:04")
+	if e.Kind == Note {
+		return fmt.Sprintf("- %s %s", stamp, e.Text)
+	}
+	return fmt.Sprintf("- %s %s [[%s]]", stamp, e.Kind, e.Ticket)
+}
+
+var (
+	entryPattern = regexp.MustCompile(`^[-*] (\d{1,2}):(\d{2}) (.*\S)`)
+	timerPattern = regexp.MustCompile(`^(start|stop) \[\[([^\]|#]+)`)
+)
+
+// Parse reads the log entries in the Log section of the day note for date. Times before the day
+// starts, like 01:30 with a 4am start, were written after midnight and are on the next calendar day.
+func Parse(content []byte, date time.Time, day clock.Day) []Entry {
+	var entries []Entry
+	inLog := false
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if strings.HasPrefix(line, "#") {
+			inLog = line == Heading
+			continue
+		}
+		if !inLog {
+			continue
+		}
+		match := entryPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		var hour, minute int
+		fmt.Sscanf(match[1]+" "+match[2], "%d %d", &hour, &minute)
+		at := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, date.Location())
+		if at.Before(day.Start(date)) {
+			at = at.AddDate(0, 0, 1)
+		}
+
+		entry := Entry{Time: at, Kind: Note, Text: match[3]}
+		if timer := timerPattern.FindStringSubmatch(match[3]); timer != nil {
+			entry = Entry{Time: at, Kind: Kind(timer[1]), Ticket: timer[2]}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// Span is a stretch of time spent on a ticket.
+type Span struct {
+	Ticket string
+	Start  time.Time
+	End    time.Time
+}
+
+func (s Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Spans pairs up the starts and stops in the entries. A start while another ticket's timer is running
+// stops that one. A timer still running at the end is counted up to until, or left out when until is zero.
+func Spans(entries []Entry, until time.Time) []Span {
+	sorted := append([]Entry{}, entries...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	var spans []Span
+	var running *Entry
+	for i, e := range sorted {
+		switch e.Kind {
+		case Start:
+			if running != nil {
+				spans = append(spans, Span{Ticket: running.Ticket, Start: running.Time, End: e.Time})
+			}
+			running = &sorted[i]
+		case Stop:
+			if running != nil && strings.EqualFold(running.Ticket, e.Ticket) {
+				spans = append(spans, Span{Ticket: running.Ticket, Start: running.Time, End: e.Time})
+				running = nil
+			}
+		}
+	}
+	if running != nil && !until.IsZero() && until.After(running.Time) {
+		spans = append(spans, Span{Ticket: running.Ticket, Start: running.Time, End: until})
+	}
+	return spans
+}
+
+// Clip returns the parts of the spans between from and to.
+func Clip(spans []Span, from, to time.Time) []Span {
+	var clipped []Span
+	for _, s := range spans {
+		if s.Start.Before(from) {
+			s.Start = from
+		}
+		if s.End.After(to) {
+			s.End = to
+		}
+		if s.End.After(s.Start) {
+			clipped = append(clipped, s)
+		}
+	}
+	return clipped
+}
+
+// Sheet is the time spent on each ticket on each day.
+type Sheet struct {
+	// Days are the days the sheet covers, at midnight
+	Days []time.Time
+	// Tickets are in the order they were first worked on
+	Tickets []string
+	hours   map[string]map[time.Time]time.Duration
+}
+
+// NewSheet adds up the spans for each ticket and day from from, for days days. A span is counted on the
+// day it starts, going by day, so late nights count towards the day note they were logged in.
+func NewSheet(spans []Span, from time.Time, days int, day clock.Day) *Sheet {
+	sheet := &Sheet{hours: map[string]map[time.Time]time.Duration{}}
+	for i := 0; i < days; i++ {
+		sheet.Days = append(sheet.Days, from.AddDate(0, 0, i))
+	}
+	for _, s := range spans {
+		date := day.Of(s.Start)
+		if date.Before(from) || !date.Before(from.AddDate(0, 0, days)) {
+			continue
+		}
+		if sheet.hours[s.Ticket] == nil {
+			sheet.hours[s.Ticket] = map[time.Time]time.Duration{}
+			sheet.Tickets = append(sheet.Tickets, s.Ticket)
+		}
+		sheet.hours[s.Ticket][date] += s.Duration()
+	}
+	return sheet
+}
+
+// Time is how long was spent on the ticket on the day.
+func (s *Sheet) Time(ticket string, date time.Time) time.Duration {
+	return s.hours[ticket][date]
+}
+
+// TicketTotal is how long was spent on the ticket over the sheet.
+func (s *Sheet) TicketTotal(ticket string) time.Duration {
+	var total time.Duration
+	for _, d := range s.hours[ticket] {
+		total += d
+	}
+	return total
+}
+
+// DayTotal is how long was spent on every ticket on the day.
+func (s *Sheet) DayTotal(date time.Time) time.Duration {
+	var total time.Duration
+	for _, ticket := range s.Tickets {
+		total += s.hours[ticket][date]
+	}
+	return total
+}
+
+// Total is how long was spent over the whole sheet.
+func (s *Sheet) Total() time.Duration {
+	var total time.Duration
+	for _, ticket := range s.Tickets {
+		total += s.TicketTotal(ticket)
+	}
+	return total
+}
+
+// Hours formats a duration as decimal hours, like 1.50.
+func Hours(d time.Duration) string {
+	return fmt.Sprintf("%.2f", d.Hours())
+}
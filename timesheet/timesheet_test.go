@@ -0,0 +1,72 @@
+package timesheet
+
+import (
+	"testing"
+	"time"
+
+	"gnote/clock"
+)
+
+func at(day, hour, minute int) time.Time {
+	return time.Date(2024, time.March, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	content := "# Monday\n\n- 09:00 not in the log\n\n## Log\n\n- 09:30 start [[PROJ-1]]\n- 10:15 found the bug\n" +
+		"- 11:00 stop [[PROJ-1|the login]]\n- 01:30 start [[PROJ-2]]\n\n## Notes\n\n- 12:00 not in the log\n"
+	day := clock.Day{Location: time.UTC, StartsAt: 4 * time.Hour}
+	got := Parse([]byte(content), at(4, 0, 0), day)
+
+	expected := []Entry{
+		{Time: at(4, 9, 30), Kind: Start, Ticket: "PROJ-1"},
+		{Time: at(4, 10, 15), Kind: Note, Text: "found the bug"},
+		{Time: at(4, 11, 0), Kind: Stop, Ticket: "PROJ-1"},
+		// Before the day starts at 4am, so after midnight
+		{Time: at(5, 1, 30), Kind: Start, Ticket: "PROJ-2"},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d entries, but got %+v", len(expected), got)
+	}
+	for i, entry := range got {
+		if entry != expected[i] {
+			t.Errorf("Expected %+v, but got %+v", expected[i], entry)
+		}
+	}
+	if got[0].String() != "- 09:30 start [[PROJ-1]]" || got[1].String() != "- 10:15 found the bug" {
+		t.Errorf("Expected entries to be written as they're read, but got %q and %q", got[0], got[1])
+	}
+}
+
+func TestSheet(t *testing.T) {
+	entries := []Entry{
+		{Time: at(4, 9, 0), Kind: Start, Ticket: "PROJ-1"},
+		// Starting another ticket stops the first
+		{Time: at(4, 10, 30), Kind: Start, Ticket: "PROJ-2"},
+		{Time: at(4, 12, 0), Kind: Stop, Ticket: "PROJ-2"},
+		{Time: at(5, 9, 0), Kind: Start, Ticket: "PROJ-1"},
+		// A stop for a ticket that isn't running is ignored
+		{Time: at(5, 9, 15), Kind: Stop, Ticket: "PROJ-3"},
+	}
+
+	if spans := Spans(entries, time.Time{}); len(spans) != 2 {
+		t.Errorf("Expected the running timer to be left out without an until, but got %+v", spans)
+	}
+	spans := Spans(entries, at(5, 9, 45))
+	sheet := NewSheet(spans, at(4, 0, 0), 7, clock.Day{Location: time.UTC})
+
+	if len(sheet.Tickets) != 2 || sheet.Tickets[0] != "PROJ-1" || sheet.Tickets[1] != "PROJ-2" {
+		t.Fatalf("Expected PROJ-1 then PROJ-2, but got %v", sheet.Tickets)
+	}
+	if got := Hours(sheet.Time("PROJ-1", at(4, 0, 0))); got != "1.50" {
+		t.Errorf("Expected 1.50 hours on PROJ-1 on Monday, but got %s", got)
+	}
+	if got := Hours(sheet.TicketTotal("PROJ-1")); got != "2.25" {
+		t.Errorf("Expected 2.25 hours on PROJ-1 over the week, but got %s", got)
+	}
+	if got := Hours(sheet.DayTotal(at(4, 0, 0))); got != "3.00" {
+		t.Errorf("Expected 3.00 hours on Monday, but got %s", got)
+	}
+	if got := Hours(sheet.Total()); got != "3.75" {
+		t.Errorf("Expected 3.75 hours in the week, but got %s", got)
+	}
+}
@@ -0,0 +1,145 @@
+// Package workday does date arithmetic over working days, skipping weekends and holidays.
+package workday
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gnote/ics"
+
+	"gopkg.in/yaml.v3"
+)
+
+const dateLayout = "2006-01-02"
+
+// holidayYears is how far ahead repeating holidays from an ICS file are filled in.
+const holidayYears = 10
+
+type Calendar struct {
+	holidays map[string]string
+}
+
+// New builds a calendar where the given dates are holidays.
+func New(holidays ...time.Time) *Calendar {
+	c := &Calendar{holidays: map[string]string{}}
+	for _, h := range holidays {
+		c.holidays[h.Format(dateLayout)] = ""
+	}
+	return c
+}
+
+// Load reads holidays from an ICS file, or a YAML file shaped like
+//
+//	holidays:
+//	  - 2024-12-25
+//	  - date: 2024-12-26
+//	    name: Boxing Day
+//
+// An empty path gives a calendar that only skips weekends.
+func Load(path string) (*Calendar, error) {
+	if path == "" {
+		return New(), nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	c := New()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ics":
+		events, err := ics.Parse(file, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("reading holidays from %s: %w", path, err)
+		}
+		// Repeating holidays are expanded up to a fixed horizon, since an open-ended rule repeats forever
+		for _, event := range ics.Occurrences(events, time.Time{}, time.Now().AddDate(holidayYears, 0, 0)) {
+			// All-day events end at midnight after their last day
+			for d := event.Start; d.Before(event.End) || d.Equal(event.Start); d = d.AddDate(0, 0, 1) {
+				c.holidays[d.Format(dateLayout)] = event.Summary
+			}
+		}
+	default:
+		var holidayFile struct {
+			Holidays []holiday `yaml:"holidays"`
+		}
+		if err := yaml.NewDecoder(file).Decode(&holidayFile); err != nil {
+			return nil, fmt.Errorf("reading holidays from %s: %w", path, err)
+		}
+		for _, h := range holidayFile.Holidays {
+			date, err := time.Parse(dateLayout, h.Date)
+			if err != nil {
+				return nil, fmt.Errorf("reading holidays from %s: %w", path, err)
+			}
+			c.holidays[date.Format(dateLayout)] = h.Name
+		}
+	}
+	return c, nil
+}
+
+type holiday struct {
+	Date string `yaml:"date"`
+	Name string `yaml:"name"`
+}
+
+// UnmarshalYAML accepts either a bare date or a date with a name.
+func (h *holiday) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		h.Date = node.Value
+		return nil
+	}
+	type plain holiday
+	return node.Decode((*plain)(h))
+}
+
+// IsHoliday reports whether the date is a holiday, and its name if it has one.
+func (c *Calendar) IsHoliday(date time.Time) (string, bool) {
+	name, ok := c.holidays[date.Format(dateLayout)]
+	return name, ok
+}
+
+func (c *Calendar) IsWorkingDay(date time.Time) bool {
+	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		return false
+	}
+	_, holiday := c.IsHoliday(date)
+	return !holiday
+}
+
+// AddWorkingDays moves forward n working days from date, so three working days
+// from a Friday is the following Wednesday. Zero returns date unchanged.
+func (c *Calendar) AddWorkingDays(date time.Time, n int) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		date = date.AddDate(0, 0, step)
+		if c.IsWorkingDay(date) {
+			n--
+		}
+	}
+	return date
+}
+
+// WorkingDaysBetween counts the working days after from, up to and including to.
+// It is negative when to is before from.
+func (c *Calendar) WorkingDaysBetween(from time.Time, to time.Time) int {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, from.Location())
+	sign := 1
+	if to.Before(from) {
+		from, to, sign = to, from, -1
+	}
+	days := 0
+	for d := from.AddDate(0, 0, 1); !d.After(to); d = d.AddDate(0, 0, 1) {
+		if c.IsWorkingDay(d) {
+			days++
+		}
+	}
+	return sign * days
+}
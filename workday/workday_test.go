@@ -0,0 +1,130 @@
+package workday
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 12, 0, 0, 0, time.Local)
+}
+
+func TestAddWorkingDays(t *testing.T) {
+	christmas := New(date(2024, time.December, 25), date(2024, time.December, 26))
+
+	testCases := []struct {
+		name     string
+		calendar *Calendar
+		start    time.Time
+		days     int
+		expected time.Time
+	}{
+		{"None is due the same day", New(), date(2024, time.May, 3), 0, date(2024, time.May, 3)},
+		{"Monday plus one", New(), date(2024, time.April, 29), 1, date(2024, time.April, 30)},
+		{"Friday plus one skips the weekend", New(), date(2024, time.May, 3), 1, date(2024, time.May, 6)},
+		{"Friday plus three is Wednesday", New(), date(2024, time.May, 3), 3, date(2024, time.May, 8)},
+		{"Saturday plus one is Monday", New(), date(2024, time.May, 4), 1, date(2024, time.May, 6)},
+		{"Holidays are skipped", christmas, date(2024, time.December, 24), 1, date(2024, time.December, 27)},
+		{"Negative counts go backwards", New(), date(2024, time.May, 6), -1, date(2024, time.May, 3)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.calendar.AddWorkingDays(tc.start, tc.days)
+			if !got.Equal(tc.expected) {
+				t.Errorf("Expected %s, but got %s", tc.expected.Format("Mon 2006-01-02"), got.Format("Mon 2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	yamlPath := filepath.Join(dir, "holidays.yaml")
+	yamlHolidays := `holidays:
+  - 2024-12-25
+  - date: 2024-12-26
+    name: Boxing Day
+`
+	if err := os.WriteFile(yamlPath, []byte(yamlHolidays), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	icsPath := filepath.Join(dir, "holidays.ics")
+	icsHolidays := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;VALUE=DATE:20241225\r\n" +
+		"DTEND;VALUE=DATE:20241227\r\n" +
+		"SUMMARY:Christmas\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	if err := os.WriteFile(icsPath, []byte(icsHolidays), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{yamlPath, icsPath} {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			calendar, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load returned an error: %v", err)
+			}
+			for _, day := range []int{25, 26} {
+				if calendar.IsWorkingDay(date(2024, time.December, day)) {
+					t.Errorf("Expected 2024-12-%d to be a holiday", day)
+				}
+			}
+			if !calendar.IsWorkingDay(date(2024, time.December, 27)) {
+				t.Errorf("Expected 2024-12-27 to be a working day")
+			}
+		})
+	}
+}
+
+func TestLoadRepeatingHoliday(t *testing.T) {
+	icsPath := filepath.Join(t.TempDir(), "holidays.ics")
+	icsHolidays := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:christmas\r\n" +
+		"DTSTART;VALUE=DATE:20241225\r\n" +
+		"DTEND;VALUE=DATE:20241227\r\n" +
+		"RRULE:FREQ=YEARLY\r\n" +
+		"SUMMARY:Christmas\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	if err := os.WriteFile(icsPath, []byte(icsHolidays), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	calendar, err := Load(icsPath)
+	if err != nil {
+		t.Fatalf("Load returned an error: %v", err)
+	}
+	for _, year := range []int{2024, 2025, 2026} {
+		if name, ok := calendar.IsHoliday(date(year, time.December, 25)); !ok || name != "Christmas" {
+			t.Errorf("Expected %d-12-25 to be Christmas, got %q", year, name)
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		start    time.Time
+		days     int
+		expected time.Time
+	}{
+		{"This year's Christmas", date(2024, time.December, 24), 1, date(2024, time.December, 27)},
+		{"Next year's Christmas", date(2025, time.December, 24), 1, date(2025, time.December, 29)},
+		// 266 weekdays from the Friday before, less both years' two days of Christmas
+		{"Across both years", date(2024, time.December, 20), 262, date(2025, time.December, 29)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := calendar.AddWorkingDays(tc.start, tc.days)
+			if !got.Equal(tc.expected) {
+				t.Errorf("Expected %s, but got %s", tc.expected.Format("Mon 2006-01-02"), got.Format("Mon 2006-01-02"))
+			}
+		})
+	}
+}